@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/go-github/v37/github"
 	"github.com/thought-machine/falco-probes/internal/cmd"
 	"github.com/thought-machine/falco-probes/internal/logging"
 	"github.com/thought-machine/falco-probes/pkg/docker"
 	"github.com/thought-machine/falco-probes/pkg/falcodriverbuilder"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cache"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
 	"github.com/thought-machine/falco-probes/pkg/releasenotes"
 	"github.com/thought-machine/falco-probes/pkg/repository"
@@ -35,9 +39,14 @@ type falcoVersion struct {
 }
 
 type opts struct {
-	Parallelism int             `long:"parallelism" description:"The amount of probes to compile at the same time" default:"4"`
-	GHReleases  ghreleases.Opts `group:"github_releases" namespace:"github_releases"`
-	Positional  struct {
+	Parallelism          int             `long:"parallelism" description:"The amount of probes to compile at the same time" default:"4"`
+	ListTimeout          time.Duration   `long:"list_timeout" description:"The maximum time to spend listing the kernel packages of the operating system" default:"30m"`
+	KernelPackageTimeout time.Duration   `long:"kernel_package_timeout" description:"The maximum time to spend getting, building and publishing the probes for a single kernel package" default:"1h"`
+	PublishedWithin      time.Duration   `long:"published_within" description:"Only build probes for kernel packages published within this duration, kernel packages whose publish date is unknown are always built (default: no limit)"`
+	GHReleases           ghreleases.Opts `group:"github_releases" namespace:"github_releases"`
+	OperatingSystems     resolver.Opts   `group:"operating_systems" namespace:"operating_systems"`
+	KernelPackageCache   cache.Opts      `group:"kernel_package_cache" namespace:"kernel_package_cache"`
+	Positional           struct {
 		OperatingSystem string `positional-arg-name:"operating_system"`
 	} `positional-args:"yes" required:"true"`
 }
@@ -46,11 +55,18 @@ func main() {
 	opts := &opts{}
 	cmd.MustParseFlags(opts)
 
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
 	cli := docker.MustClient()
 	ghReleases := ghreleases.MustGHReleases(&opts.GHReleases)
 
 	log.Info().Msg("Getting list of falco drivers")
-	FalcoVersions, err := getFalcoDrivers(cli, FalcoVersionNames)
+	FalcoVersions, err := getFalcoDrivers(ctx, cli, FalcoVersionNames)
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not get falco drivers")
 	}
@@ -65,28 +81,38 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not get operating system")
 	}
+	operatingSystem = cache.Wrap(&opts.KernelPackageCache, cli, operatingSystem)
 
 	log.Info().
 		Str("operating_system", opts.Positional.OperatingSystem).
 		Msg("Getting list of kernel packages")
-	kernelPackageNames, err := operatingSystem.GetKernelPackageNames()
+	listCtx, listCancel := context.WithTimeout(ctx, opts.ListTimeout)
+	kernelPackageRefs, err := operatingSystem.GetKernelPackageNames(listCtx)
+	listCancel()
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not get kernel package names")
 	}
 
 	log.Info().
-		Int("total_kernel_packages", len(kernelPackageNames)).
+		Int("total_kernel_packages", len(kernelPackageRefs)).
 		Msg("Retrieving kernel packages")
 
+	if opts.PublishedWithin > 0 {
+		kernelPackageRefs = kernelPackageRefs.PublishedSince(time.Now().Add(-opts.PublishedWithin))
+		log.Info().
+			Dur("published_within", opts.PublishedWithin).
+			Int("kernel_packages", len(kernelPackageRefs)).
+			Msg("ignoring kernel packages published earlier")
+	}
+	// Build the newest kernel packages first as they are the most likely to be needed.
+	kernelPackageRefs = kernelPackageRefs.NewestFirst()
+	kernelPackageNames := kernelPackageRefs.Names()
+
 	log.Info().Msg("Getting list of previously compiled probes from release notes")
 	if releases, err := ghReleases.GetReleases(); err != nil {
 		log.Warn().Err(err).Msg("could not get release notes. unable to skip previously compiled probes")
 	} else {
-		var releasedProbes releasenotes.ReleasedProbes
-		for _, r := range releases {
-			releasedProbes = append(releasedProbes, releasenotes.ParseProbesFromReleaseNotes(r)...)
-		}
-		kernelPackageNames = releasedProbes.ListKernelPackagesToCompile(kernelPackageNames, len(releases))
+		kernelPackageNames = listKernelPackagesToCompile(kernelPackageRefs, releases)
 
 		log.Info().
 			Int("kernel_packages_to_compile", len(kernelPackageNames)).
@@ -99,7 +125,11 @@ func main() {
 		kernelPackageName := kernelPackageName
 
 		parallelFns = append(parallelFns, func() error {
+			kernelPackageCtx, kernelPackageCancel := context.WithTimeout(ctx, opts.KernelPackageTimeout)
+			defer kernelPackageCancel()
+
 			return process1KernelPackage(
+				kernelPackageCtx,
 				cli,
 				ghReleases,
 				operatingSystem,
@@ -111,10 +141,16 @@ func main() {
 
 	errs := cmd.RunParallelAndCollectErrors(parallelFns, opts.Parallelism)
 
+	// Volumes of kernel packages which failed to hydrate, or were interrupted, are not removed by process1KernelPackage.
+	if err := cli.RemoveCreatedVolumes(); err != nil {
+		log.Warn().Err(err).Msg("could not remove left over docker volumes")
+	}
+
 	handleErrs(errs)
 }
 
 func process1KernelPackage(
+	ctx context.Context,
 	dockerCli *docker.Client,
 	repo repository.Repository,
 	operatingSystem operatingsystem.OperatingSystem,
@@ -125,7 +161,11 @@ func process1KernelPackage(
 	log.Info().
 		Str("kernel_package_name", kernelPackageName).
 		Msg("Getting kernel_package for")
-	kernelPackage, err := operatingSystem.GetKernelPackageByName(kernelPackageName)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("skipped kernel package '%s': %w", kernelPackageName, err)
+	}
+
+	kernelPackage, err := operatingSystem.GetKernelPackageByName(ctx, kernelPackageName)
 	if err != nil {
 		return fmt.Errorf("could not get kernel package '%s': %w", kernelPackageName, err)
 	}
@@ -143,12 +183,22 @@ func process1KernelPackage(
 		Msg("Got kernel_package")
 
 	for _, falcoVersion := range falcoVersions {
+		if !kernelPackage.SupportsFalcoVersion(falcoVersion.Name) {
+			log.Info().
+				Str("falco_version", falcoVersion.Name).
+				Str("minimum_falco_version", kernelPackage.MinimumFalcoVersion()).
+				Str("probe_name", probeName).
+				Msg("Skipping, falco version does not support kernel package")
+			continue
+		}
+
 		// Check if probe is already mirrored to our repository & doesn't require building
 		log.Info().
 			Str("driver", falcoVersion.Driver).
 			Str("probe_name", probeName).
 			Msg("Checking whether probe is built & published")
-		alreadyPublished, err := repo.IsAlreadyMirrored(falcoVersion.Driver, probeName)
+		driverVersionPath := operatingsystem.DriverVersionPath(falcoVersion.Driver, kernelPackage.KernelMachine)
+		alreadyPublished, err := repo.IsAlreadyMirrored(driverVersionPath, probeName)
 		if err != nil {
 			log.Error().Err(err).Msg("") // will just be logged as if probe is unfound it makes sense to try to build & publish it
 		}
@@ -165,6 +215,7 @@ func process1KernelPackage(
 				Str("probe_name", probeName).
 				Msg("Not found, probe will now be built")
 			builtDriverVersion, probePath, err := falcodriverbuilder.BuildEBPFProbe(
+				ctx,
 				dockerCli,
 				falcoVersion.Name,
 				operatingSystem,
@@ -188,7 +239,50 @@ func process1KernelPackage(
 	return nil
 }
 
-func getFalcoDrivers(dockerCli *docker.Client, FalcoVersionNames []string) ([]falcoVersion, error) {
+// listKernelPackagesToCompile returns the names of the given kernel packages, in order, which have not been released
+// for all Falco versions according to the notes of the given releases. Probes for each machine are released separately
+// (see operatingsystem.DriverVersionPath), so kernel packages are only cross-referenced against the releases for their
+// machine, and are all compiled if there are none yet.
+func listKernelPackagesToCompile(kernelPackageRefs operatingsystem.KernelPackageRefs, releases []*github.RepositoryRelease) []string {
+	namesByMachine := map[string][]string{}
+	for _, ref := range kernelPackageRefs {
+		machine := ref.Architecture
+		if machine == "" {
+			machine = operatingsystem.MachineX86_64
+		}
+		namesByMachine[machine] = append(namesByMachine[machine], ref.Name)
+	}
+
+	toCompile := map[string]bool{}
+	for machine, names := range namesByMachine {
+		var releasedProbes releasenotes.ReleasedProbes
+		numReleases := 0
+		for _, r := range releases {
+			if _, releaseMachine := operatingsystem.ParseDriverVersionPath(r.GetName()); releaseMachine != machine {
+				continue
+			}
+			releasedProbes = append(releasedProbes, releasenotes.ParseProbesFromReleaseNotes(r)...)
+			numReleases++
+		}
+		if numReleases > 0 {
+			names = releasedProbes.ListKernelPackagesToCompile(names, numReleases)
+		}
+		for _, name := range names {
+			toCompile[name] = true
+		}
+	}
+
+	kernelPackageNames := []string{}
+	for _, ref := range kernelPackageRefs {
+		if toCompile[ref.Name] {
+			kernelPackageNames = append(kernelPackageNames, ref.Name)
+		}
+	}
+
+	return kernelPackageNames
+}
+
+func getFalcoDrivers(ctx context.Context, dockerCli *docker.Client, FalcoVersionNames []string) ([]falcoVersion, error) {
 	var FalcoVersions []falcoVersion
 
 	for _, falcoVersionName := range FalcoVersionNames {
@@ -199,7 +293,7 @@ func getFalcoDrivers(dockerCli *docker.Client, FalcoVersionNames []string) ([]fa
 		if err != nil {
 			return FalcoVersions, fmt.Errorf("could not get falco_driver_builder_image for %s:%w", falcoVersionName, err)
 		}
-		driver, err := falcodriverbuilder.GetDriverVersion(dockerCli, falcoDriverBuilderImg)
+		driver, err := falcodriverbuilder.GetDriverVersion(ctx, dockerCli, falcoDriverBuilderImg)
 		if err != nil {
 			return FalcoVersions, fmt.Errorf("could not get driver for %s:%w", falcoDriverBuilderImg, err)
 		}
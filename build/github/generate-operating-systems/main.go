@@ -9,11 +9,15 @@ import (
 
 	"github.com/thought-machine/falco-probes/internal/cmd"
 	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/local"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
 )
 
 type opts struct {
-	OutFile string `long:"out_file" description:"The path to a file to output the list of jobs for GitHub Actions" required:"yes"`
+	OutFile          string        `long:"out_file" description:"The path to a file to output the list of jobs for GitHub Actions" required:"yes"`
+	OperatingSystems resolver.Opts `group:"operating_systems" namespace:"operating_systems"`
 }
 
 var log = logging.Logger
@@ -21,10 +25,14 @@ var log = logging.Logger
 // Jobs represents a json-ifiable structure of jobs for producing a job matrix on GitHub Actions.
 type Jobs []string
 
-// JobsPerOperatingSystem returns Jobs per supported operating system.
+// JobsPerOperatingSystem returns Jobs per supported operating system, except for local and archived kernels which are
+// only built on demand from their manifests and archives, and aliases of other operating systems.
 func JobsPerOperatingSystem() Jobs {
 	jobs := Jobs{}
 	for os := range resolver.OperatingSystems {
+		if os == local.Name || os == archive.Name || os == ubuntu.Name {
+			continue
+		}
 		jobs = append(jobs, os)
 	}
 
@@ -53,6 +61,10 @@ func main() {
 	opts := &opts{}
 	cmd.MustParseFlags(opts)
 
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
 	jobs := JobsPerOperatingSystem()
 	log.Info().
 		Int("amount", len(jobs)).
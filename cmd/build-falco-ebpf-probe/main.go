@@ -5,12 +5,15 @@ import (
 	"github.com/thought-machine/falco-probes/internal/logging"
 	"github.com/thought-machine/falco-probes/pkg/docker"
 	"github.com/thought-machine/falco-probes/pkg/falcodriverbuilder"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cache"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
 )
 
 type opts struct {
-	FalcoVersion string `long:"falco_version" description:"The version of Falco to compile probes against" required:"true"`
-	Positional   struct {
+	FalcoVersion       string        `long:"falco_version" description:"The version of Falco to compile probes against" required:"true"`
+	OperatingSystems   resolver.Opts `group:"operating_systems" namespace:"operating_systems"`
+	KernelPackageCache cache.Opts    `group:"kernel_package_cache" namespace:"kernel_package_cache"`
+	Positional         struct {
 		OperatingSystem string `positional-arg-name:"operating_system"`
 		KernelPackage   string `positional-arg-name:"kernel_package"`
 	} `positional-args:"yes" required:"true"`
@@ -22,6 +25,13 @@ func main() {
 	opts := &opts{}
 	cmd.MustParseFlags(opts)
 
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
 	cli := docker.MustClient()
 
 	log.Info().
@@ -31,18 +41,22 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not get operating system")
 	}
+	operatingSystem = cache.Wrap(&opts.KernelPackageCache, cli, operatingSystem)
 
-	kernelPackage, err := operatingSystem.GetKernelPackageByName(opts.Positional.KernelPackage)
+	kernelPackage, err := operatingSystem.GetKernelPackageByName(ctx, opts.Positional.KernelPackage)
 	if err != nil {
+		removeCreatedVolumes(cli)
 		log.Fatal().Err(err).Msg("could not get kernel package")
 	}
 
 	if _, _, err := falcodriverbuilder.BuildEBPFProbe(
+		ctx,
 		cli,
 		opts.FalcoVersion,
 		operatingSystem,
 		kernelPackage,
 	); err != nil {
+		removeCreatedVolumes(cli)
 		log.Fatal().Err(err).Msg("could not build eBPF probe")
 	}
 
@@ -50,4 +64,12 @@ func main() {
 		kernelPackage.KernelSources,
 		kernelPackage.KernelConfiguration,
 	)
+	removeCreatedVolumes(cli)
+}
+
+// removeCreatedVolumes removes the docker volumes which are left over, e.g. from a failed or interrupted build.
+func removeCreatedVolumes(cli *docker.Client) {
+	if err := cli.RemoveCreatedVolumes(); err != nil {
+		log.Warn().Err(err).Msg("could not remove left over docker volumes")
+	}
 }
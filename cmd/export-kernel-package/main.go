@@ -0,0 +1,102 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/internal/cmd"
+	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cache"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
+)
+
+type opts struct {
+	OutFile            string        `long:"out_file" description:"The path to export the kernel package to as a tar archive, compressed with gzip if it ends with .gz" required:"true"`
+	OperatingSystems   resolver.Opts `group:"operating_systems" namespace:"operating_systems"`
+	KernelPackageCache cache.Opts    `group:"kernel_package_cache" namespace:"kernel_package_cache"`
+	Positional         struct {
+		OperatingSystem string `positional-arg-name:"operating_system"`
+		KernelPackage   string `positional-arg-name:"kernel_package"`
+	} `positional-args:"yes" required:"true"`
+}
+
+var log = logging.Logger
+
+func main() {
+	opts := &opts{}
+	cmd.MustParseFlags(opts)
+
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
+	cli := docker.MustClient()
+
+	log.Info().
+		Str("operating_system", opts.Positional.OperatingSystem).
+		Msg("Resolving operating system")
+	operatingSystem, err := resolver.OperatingSystem(cli, opts.Positional.OperatingSystem)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get operating system")
+	}
+	operatingSystem = cache.Wrap(&opts.KernelPackageCache, cli, operatingSystem)
+
+	kernelPackage, err := operatingSystem.GetKernelPackageByName(ctx, opts.Positional.KernelPackage)
+	if err != nil {
+		removeCreatedVolumes(cli)
+		log.Fatal().Err(err).Msg("could not get kernel package")
+	}
+
+	if err := exportToFile(ctx, cli, kernelPackage, opts.OutFile); err != nil {
+		removeCreatedVolumes(cli)
+		log.Fatal().Err(err).Msg("could not export kernel package")
+	}
+	log.Info().
+		Str("path", opts.OutFile).
+		Msg("exported kernel package to file")
+
+	cli.MustRemoveVolumes(
+		kernelPackage.KernelSources,
+		kernelPackage.KernelConfiguration,
+	)
+	removeCreatedVolumes(cli)
+}
+
+// exportToFile exports the given kernel package to the given path, compressing it with gzip if the path ends with .gz.
+func exportToFile(ctx context.Context, cli *docker.Client, kernelPackage *operatingsystem.KernelPackage, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var w io.WriteCloser = f
+	if strings.HasSuffix(path, ".gz") {
+		w = gzip.NewWriter(f)
+	}
+
+	if err := archive.Export(ctx, cli, kernelPackage, w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// removeCreatedVolumes removes the docker volumes which are left over, e.g. from a failed or interrupted export.
+func removeCreatedVolumes(cli *docker.Client) {
+	if err := cli.RemoveCreatedVolumes(); err != nil {
+		log.Warn().Err(err).Msg("could not remove left over docker volumes")
+	}
+}
@@ -7,15 +7,19 @@ import (
 	"github.com/thought-machine/falco-probes/internal/logging"
 	"github.com/thought-machine/falco-probes/pkg/docker"
 	"github.com/thought-machine/falco-probes/pkg/falcodriverbuilder"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cache"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
 	"github.com/thought-machine/falco-probes/pkg/repository"
 	"github.com/thought-machine/falco-probes/pkg/repository/ghreleases"
 )
 
 type opts struct {
-	FalcoVersion string          `long:"falco_version" description:"The version of Falco to compile probes against" required:"true"`
-	GHReleases   ghreleases.Opts `group:"github_releases" namespace:"github_releases"`
-	Positional   struct {
+	FalcoVersion       string          `long:"falco_version" description:"The version of Falco to compile probes against" required:"true"`
+	GHReleases         ghreleases.Opts `group:"github_releases" namespace:"github_releases"`
+	OperatingSystems   resolver.Opts   `group:"operating_systems" namespace:"operating_systems"`
+	KernelPackageCache cache.Opts      `group:"kernel_package_cache" namespace:"kernel_package_cache"`
+	Positional         struct {
 		OperatingSystem string `positional-arg-name:"operating_system"`
 		KernelPackage   string `positional-arg-name:"kernel_package"`
 	} `positional-args:"yes" required:"true"`
@@ -27,6 +31,13 @@ func main() {
 	opts := &opts{}
 	cmd.MustParseFlags(opts)
 
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
 	cli := docker.MustClient()
 	var repo repository.Repository = ghreleases.MustGHReleases(&opts.GHReleases)
 
@@ -36,7 +47,7 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not get driver builder image for provided falco_version")
 	}
-	driverVersion, err := falcodriverbuilder.GetDriverVersion(cli, falcoDriverBuilderImg)
+	driverVersion, err := falcodriverbuilder.GetDriverVersion(ctx, cli, falcoDriverBuilderImg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not get driver version for provided falco_version")
 	}
@@ -48,7 +59,11 @@ func main() {
 	}
 
 	log.Info().Str("kernel_package", opts.Positional.KernelPackage).Msg("Verifying input")
-	kernelPackage, err := operatingSystem.GetKernelPackageByName(opts.Positional.KernelPackage)
+	kernelPackage, err := operatingSystem.GetKernelPackageByName(ctx, opts.Positional.KernelPackage)
+	// Only the kernel package's details are needed from here on, so its volumes can be removed straight away.
+	if remErr := cli.RemoveCreatedVolumes(); remErr != nil {
+		log.Warn().Err(remErr).Msg("could not remove left over docker volumes")
+	}
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not get kernel package")
 	}
@@ -58,6 +73,7 @@ func main() {
 	}
 
 	probeName := kernelPackage.ProbeName() + ".o"
+	driverVersion = operatingsystem.DriverVersionPath(driverVersion, kernelPackage.KernelMachine)
 
 	// Indentify if probe uploaded
 	log.Info().
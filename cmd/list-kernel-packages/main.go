@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -12,8 +13,10 @@ import (
 )
 
 type opts struct {
-	OutFile    string `long:"out_file" description:"The path to a file to output a list of Falco probes too (default: output to stdout)"`
-	Positional struct {
+	OutFile          string        `long:"out_file" description:"The path to a file to output a list of Falco probes too (default: output to stdout)"`
+	Format           string        `long:"format" description:"The format to output the kernel packages in, either their names one per line or a JSON list of references with what is known about them" choice:"names" choice:"json" default:"names"`
+	OperatingSystems resolver.Opts `group:"operating_systems" namespace:"operating_systems"`
+	Positional       struct {
 		OperatingSystem string `positional-arg-name:"operating_system"`
 	} `positional-args:"yes" required:"true"`
 }
@@ -24,6 +27,13 @@ func main() {
 	opts := &opts{}
 	cmd.MustParseFlags(opts)
 
+	if err := resolver.Configure(&opts.OperatingSystems); err != nil {
+		log.Fatal().Err(err).Msg("could not configure operating systems")
+	}
+
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
 	cli := docker.MustClient()
 
 	log.Info().
@@ -37,17 +47,24 @@ func main() {
 	log.Info().
 		Str("operating_system", opts.Positional.OperatingSystem).
 		Msg("Getting kernel package names")
-	kernelPackageNames, err := operatingSystem.GetKernelPackageNames()
+	kernelPackageRefs, err := operatingSystem.GetKernelPackageNames(ctx)
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not kernel package names")
 	}
 
 	log.Info().
-		Int("amount", len(kernelPackageNames)).
+		Int("amount", len(kernelPackageRefs)).
 		Str("operating_system", opts.Positional.OperatingSystem).
 		Msg("got kernel packages")
 
-	output := strings.Join(kernelPackageNames, "\n")
+	output := strings.Join(kernelPackageRefs.Names(), "\n")
+	if opts.Format == "json" {
+		refsJSON, err := json.MarshalIndent(kernelPackageRefs, "", "  ")
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not marshal kernel package references")
+		}
+		output = string(refsJSON)
+	}
 
 	if len(opts.OutFile) > 0 {
 		ioutil.WriteFile(opts.OutFile, []byte(output), 0644)
@@ -0,0 +1,46 @@
+package atomicfile_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/internal/atomicfile"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foo", "bar")
+
+	err := atomicfile.WriteFile(path, func(w io.Writer) error {
+		_, err := w.Write([]byte("bar"))
+		return err
+	})
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(contents))
+
+	// a failed write leaves the existing file and no temporary files behind.
+	err = atomicfile.WriteFile(path, func(w io.Writer) error {
+		_, err := w.Write([]byte("baz"))
+		if err != nil {
+			return err
+		}
+		return errors.New("failed")
+	})
+	assert.Error(t, err)
+
+	contents, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(contents))
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "foo"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
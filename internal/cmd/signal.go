@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/thought-machine/falco-probes/internal/logging"
+)
+
+// SignalContext returns a context which is cancelled when the process is interrupted (e.g. Ctrl-C) or terminated, so
+// that in-flight work such as docker containers can be stopped and cleaned up before exiting.
+// Receiving a second signal exits immediately.
+func SignalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			logging.Logger.Warn().
+				Str("signal", sig.String()).
+				Msg("cancelling and cleaning up, signal again to exit immediately")
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+		cancel()
+	}()
+
+	return ctx, cancel
+}
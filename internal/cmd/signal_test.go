@@ -0,0 +1,23 @@
+package cmd_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/internal/cmd"
+)
+
+func TestSignalContext(t *testing.T) {
+	ctx, cancel := cmd.SignalContext()
+	defer cancel()
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "context was not cancelled by SIGTERM")
+	}
+}
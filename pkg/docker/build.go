@@ -16,6 +16,10 @@ type BuildOpts struct {
 	Dockerfile string
 	BuildArgs  map[string]*string
 	Tags       []string
+	// Platform is the platform to build the image for (e.g. `linux/arm64`), defaulting to the Docker daemon's platform.
+	// Images for other platforms are built and run with emulation, which must be set up on the host (e.g. with
+	// `docker run --privileged --rm tonistiigi/binfmt --install all`).
+	Platform string
 }
 
 // Build builds a docker image with the given options.
@@ -33,6 +37,7 @@ func (c *Client) Build(opts *BuildOpts) error {
 		Dockerfile: "Dockerfile",
 		Tags:       opts.Tags,
 		BuildArgs:  opts.BuildArgs,
+		Platform:   opts.Platform,
 	})
 	if err != nil {
 		return fmt.Errorf("could not build docker image: %w", err)
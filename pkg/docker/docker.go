@@ -1,8 +1,11 @@
 package docker
 
 import (
+	"sync"
+
 	"github.com/docker/docker/client"
 	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 )
 
 var log = logging.Logger
@@ -10,6 +13,10 @@ var log = logging.Logger
 // Client abstracts the docker client into useful functions.
 type Client struct {
 	upstream *client.Client
+
+	// volumes are the volumes created by this client which have not been removed yet.
+	volumes   map[operatingsystem.Volume]struct{}
+	volumesMu sync.Mutex
 }
 
 // MustClient returns a new docker client, fatally logging any errors.
@@ -19,5 +26,8 @@ func MustClient() *Client {
 		log.Fatal().Err(err).Msg("could not initialise docker client")
 	}
 
-	return &Client{upstream: cli}
+	return &Client{
+		upstream: cli,
+		volumes:  map[operatingsystem.Volume]struct{}{},
+	}
 }
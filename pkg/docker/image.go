@@ -10,8 +10,12 @@ import (
 // EnsureImage ensures that the given image exists locally in Docker and can be used for creating containers
 // by checking if the given image exists, pulling it if not.
 func (c *Client) EnsureImage(image string) error {
+	return c.ensureImage(context.Background(), image)
+}
+
+// ensureImage is EnsureImage, aborting any pull when the given context is cancelled.
+func (c *Client) ensureImage(ctx context.Context, image string) error {
 	if !c.imageExists(image) {
-		ctx := context.Background()
 		reader, err := c.upstream.ImagePull(ctx, image, types.ImagePullOptions{})
 		if err != nil {
 			return err
@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// GetOSRelease returns the contents of `/etc/os-release` in the given image.
+func (c *Client) GetOSRelease(ctx context.Context, image string) (operatingsystem.FileContents, error) {
+	osReleaseVol := c.MustCreateVolume()
+	defer c.MustRemoveVolumes(osReleaseVol)
+
+	_, err := c.Run(
+		ctx,
+		&RunOpts{
+			Image:      image,
+			Entrypoint: []string{"cp"},
+			Cmd:        []string{"/etc/os-release", "/host/etc/os-release"},
+			Volumes: map[operatingsystem.Volume]string{
+				osReleaseVol: "/host/etc/",
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	fileReader, err := c.GetFileFromVolume(osReleaseVol, "/host/etc/", "/host/etc/os-release")
+	if err != nil {
+		return "", err
+	}
+
+	fileContents, err := ioutil.ReadAll(fileReader)
+	if err != nil {
+		return "", err
+	}
+
+	return operatingsystem.FileContents(fileContents), nil
+}
+
+// GetKernelRelease returns the kernel release of the single kernel build tree in `/usr/src/kernels/<kernel release>`
+// of the given kernel sources volume, which is mounted at `/usr/src/`.
+func (c *Client) GetKernelRelease(ctx context.Context, kernelSources operatingsystem.Volume) (string, error) {
+	out, err := c.Run(
+		ctx,
+		&RunOpts{
+			Image:      BusyBoxImage,
+			Entrypoint: []string{"ls"},
+			Cmd:        []string{"/usr/src/kernels/"},
+			Volumes: map[operatingsystem.Volume]string{
+				kernelSources: "/usr/src/",
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	kernelRelease := strings.TrimSpace(out)
+	if kernelRelease == "" || strings.ContainsAny(kernelRelease, " \n") {
+		return "", fmt.Errorf("could not find a single kernel release in the kernel sources: %s", out)
+	}
+
+	return kernelRelease, nil
+}
+
+// GetGeneratedDefine returns the value of the given define, e.g. UTS_VERSION, in the generated headers of the kernel
+// build tree of the given kernel release in the given kernel sources volume, which is mounted at `/usr/src/`. Kernels
+// >= 5.19 moved UTS_VERSION from `generated/compile.h` to `generated/utsversion.h` so both are searched.
+func (c *Client) GetGeneratedDefine(ctx context.Context, kernelSources operatingsystem.Volume, kernelRelease string, define string) (string, error) {
+	script := `cd /usr/src/kernels/%s/include/generated && cat compile.h utsversion.h 2>/dev/null | grep -o '%s ".*"' | head -n1 | cut -d'"' -f2`
+
+	out, err := c.Run(
+		ctx,
+		&RunOpts{
+			Image:      BusyBoxImage,
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kernelRelease, define)},
+			Volumes: map[operatingsystem.Volume]string{
+				kernelSources: "/usr/src/",
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSpace(out)
+	if value == "" {
+		return "", fmt.Errorf("could not find %s in the kernel sources for %s", define, kernelRelease)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,44 @@
+package docker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+func TestGetKernelReleaseAndGeneratedDefine(t *testing.T) {
+	cli := docker.MustClient()
+
+	vol := cli.MustCreateVolume()
+	defer cli.MustRemoveVolumes(vol)
+
+	_, err := cli.Run(context.Background(), &docker.RunOpts{
+		Image:      docker.BusyBoxImage,
+		Entrypoint: []string{"/bin/sh"},
+		Cmd: []string{"-c", `mkdir -p /usr/src/kernels/6.1.0-test/include/generated && cd /usr/src/kernels/6.1.0-test/include/generated && ` +
+			`echo '#define UTS_MACHINE "x86_64"' > compile.h && echo '#define UTS_VERSION "#1 SMP Mon Apr 17 10:00:00 UTC 2023"' > utsversion.h`},
+		Volumes: map[operatingsystem.Volume]string{
+			vol: "/usr/src/",
+		},
+	})
+	require.NoError(t, err)
+
+	kernelRelease, err := cli.GetKernelRelease(context.Background(), vol)
+	require.NoError(t, err)
+	assert.Equal(t, "6.1.0-test", kernelRelease)
+
+	kernelVersion, err := cli.GetGeneratedDefine(context.Background(), vol, kernelRelease, "UTS_VERSION")
+	require.NoError(t, err)
+	assert.Equal(t, "#1 SMP Mon Apr 17 10:00:00 UTC 2023", kernelVersion)
+
+	kernelMachine, err := cli.GetGeneratedDefine(context.Background(), vol, kernelRelease, "UTS_MACHINE")
+	require.NoError(t, err)
+	assert.Equal(t, "x86_64", kernelMachine)
+
+	_, err = cli.GetGeneratedDefine(context.Background(), vol, kernelRelease, "LINUX_COMPILE_BY")
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package docker
+
+import "github.com/thought-machine/falco-probes/pkg/operatingsystem"
+
+// PlatformForMachine returns the platform (see BuildOpts.Platform) to build images in for kernels of the given
+// KernelMachine (`uname -m`). x86_64 kernels, and those of unknown machines, are built on the Docker daemon's platform.
+func PlatformForMachine(kernelMachine string) string {
+	switch kernelMachine {
+	case operatingsystem.MachineAarch64:
+		return "linux/arm64"
+	default:
+		return ""
+	}
+}
+
+// TagForMachine returns the given image tag qualified with the given KernelMachine (`uname -m`), so that images built
+// for different platforms do not replace each other. Tags for x86_64 are left as they are.
+func TagForMachine(tag string, kernelMachine string) string {
+	if kernelMachine == "" || kernelMachine == operatingsystem.MachineX86_64 {
+		return tag
+	}
+
+	return tag + "-" + kernelMachine
+}
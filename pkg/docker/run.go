@@ -22,17 +22,16 @@ type RunOpts struct {
 }
 
 // Run runs the given docker image, returning its output as a string.
+// The container is killed and removed if the given context is cancelled before it exits.
 // TODO: break down into smaller functions
-func (c *Client) Run(opts *RunOpts) (containerOut string, err error) {
-	ctx := context.Background()
-
+func (c *Client) Run(ctx context.Context, opts *RunOpts) (containerOut string, err error) {
 	log.Debug().
 		Strs("entrypoint", opts.Entrypoint).
 		Strs("cmd", opts.Cmd).
 		Str("image", opts.Image).
 		Msg("docker run")
 
-	if err := c.EnsureImage(opts.Image); err != nil {
+	if err := c.ensureImage(ctx, opts.Image); err != nil {
 		return "", err
 	}
 
@@ -50,7 +49,9 @@ func (c *Client) Run(opts *RunOpts) (containerOut string, err error) {
 	}
 
 	defer func() {
-		if remErr := c.upstream.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{RemoveVolumes: true}); remErr != nil {
+		// the given context may already be cancelled, but the container should still be removed.
+		remOpts := types.ContainerRemoveOptions{RemoveVolumes: true, Force: true}
+		if remErr := c.upstream.ContainerRemove(context.Background(), resp.ID, remOpts); remErr != nil && err == nil {
 			err = remErr
 		}
 	}()
@@ -0,0 +1,26 @@
+package docker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+func TestRunCancelled(t *testing.T) {
+	cli := docker.MustClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := cli.Run(ctx, &docker.RunOpts{
+		Image:      docker.BusyBoxImage,
+		Entrypoint: []string{"sleep"},
+		Cmd:        []string{"300"},
+	})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start).Seconds(), 60.0)
+}
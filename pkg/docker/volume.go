@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -28,6 +29,10 @@ func (c *Client) MustCreateVolume() operatingsystem.Volume {
 		log.Fatal().Err(err).Msg("could not create docker volume")
 	}
 
+	c.volumesMu.Lock()
+	defer c.volumesMu.Unlock()
+	c.volumes[operatingsystem.Volume(vol.Name)] = struct{}{}
+
 	return operatingsystem.Volume(vol.Name)
 }
 
@@ -39,13 +44,47 @@ func (c *Client) MustRemoveVolumes(volumeNames ...operatingsystem.Volume) {
 		err := c.upstream.VolumeRemove(ctx, string(volumeName), false)
 		if err != nil {
 			errs = append(errs, err)
+			continue
 		}
+		c.forgetVolume(volumeName)
 	}
 	if len(errs) > 0 {
 		log.Fatal().Errs("errors", errs).Msg("could not remove docker volume(s)")
 	}
 }
 
+// RemoveCreatedVolumes removes all of the volumes created by this client which have not been removed yet, e.g. those
+// left behind by a kernel package whose hydration failed or was cancelled. This should be called before exiting.
+func (c *Client) RemoveCreatedVolumes() error {
+	c.volumesMu.Lock()
+	volumeNames := []operatingsystem.Volume{}
+	for volumeName := range c.volumes {
+		volumeNames = append(volumeNames, volumeName)
+	}
+	c.volumesMu.Unlock()
+
+	ctx := context.Background()
+	errs := []string{}
+	for _, volumeName := range volumeNames {
+		if err := c.upstream.VolumeRemove(ctx, string(volumeName), true); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		c.forgetVolume(volumeName)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("could not remove docker volume(s): %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (c *Client) forgetVolume(volumeName operatingsystem.Volume) {
+	c.volumesMu.Lock()
+	defer c.volumesMu.Unlock()
+	delete(c.volumes, volumeName)
+}
+
 // WriteFileToVolume writes the given contents to the given path with the given volume and its mount point.
 // TODO: split into smaller functions
 func (c *Client) WriteFileToVolume(volume operatingsystem.Volume, volumeMnt string, path string, contents string) error {
@@ -98,6 +137,22 @@ func (c *Client) WriteFileToVolume(volume operatingsystem.Volume, volumeMnt stri
 	return c.upstream.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{})
 }
 
+// WriteTarToVolume extracts the given tar archive, which may be compressed with gzip, bzip2 or xz, into the given
+// directory with the given volume and its mount point. The copy is aborted when the given context is cancelled.
+func (c *Client) WriteTarToVolume(ctx context.Context, volume operatingsystem.Volume, volumeMnt string, dir string, archive io.Reader) (err error) {
+	containerID, err := c.createVolumeContainer(ctx, volume, volumeMnt)
+	if err != nil {
+		return err
+	}
+	defer c.removeVolumeContainer(containerID, &err)
+
+	if err := c.upstream.CopyToContainer(ctx, containerID, dir, archive, types.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("could not copy to container: %w", err)
+	}
+
+	return nil
+}
+
 // GetFileFromVolume returns a reader for the contents of a file in the given volume and path.
 // TODO: split into smaller functions
 func (c *Client) GetFileFromVolume(volume operatingsystem.Volume, volumeMnt string, path string) (io.Reader, error) {
@@ -152,3 +207,60 @@ func (c *Client) GetFileFromVolume(volume operatingsystem.Volume, volumeMnt stri
 
 	return outBytes, nil
 }
+
+// ReadTarFromVolume writes a tar archive of the contents of the given volume, mounted at the given mount point, to the
+// given writer. The archive can be extracted into a volume again with WriteTarToVolume into the volume's mount point.
+// The copy is aborted when the given context is cancelled.
+func (c *Client) ReadTarFromVolume(ctx context.Context, volume operatingsystem.Volume, volumeMnt string, archive io.Writer) (err error) {
+	containerID, err := c.createVolumeContainer(ctx, volume, volumeMnt)
+	if err != nil {
+		return err
+	}
+	defer c.removeVolumeContainer(containerID, &err)
+
+	// a trailing `/.` copies the contents of the mount point rather than the mount point itself.
+	reader, _, err := c.upstream.CopyFromContainer(ctx, containerID, strings.TrimSuffix(volumeMnt, "/")+"/.")
+	if err != nil {
+		return fmt.Errorf("could not copy from container: %w", err)
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(archive, reader); err != nil {
+		return fmt.Errorf("could not read archive: %w", err)
+	}
+
+	return nil
+}
+
+// createVolumeContainer creates, without starting, a container with the given volume mounted at the given mount point
+// to copy files to and from the volume with, and returns its ID.
+func (c *Client) createVolumeContainer(ctx context.Context, volume operatingsystem.Volume, volumeMnt string) (string, error) {
+	if err := c.ensureImage(ctx, BusyBoxImage); err != nil {
+		return "", err
+	}
+
+	volumes := map[operatingsystem.Volume]string{
+		volume: volumeMnt,
+	}
+
+	resp, err := c.upstream.ContainerCreate(ctx, &container.Config{
+		Image:   BusyBoxImage,
+		Volumes: getContainerConfigVolumesFromOpts(volumes),
+		Tty:     false,
+	}, getHostConfigFromOpts(volumes), nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// removeVolumeContainer removes the container with the given ID, setting the given error to any error removing it
+// unless it is already set.
+func (c *Client) removeVolumeContainer(containerID string, err *error) {
+	// the given context may already be cancelled, but the container should still be removed.
+	remOpts := types.ContainerRemoveOptions{Force: true}
+	if remErr := c.upstream.ContainerRemove(context.Background(), containerID, remOpts); remErr != nil && *err == nil {
+		*err = remErr
+	}
+}
@@ -1,6 +1,8 @@
 package docker_test
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
 	"testing"
 
@@ -18,7 +20,7 @@ func TestWriteFileToVolume(t *testing.T) {
 	err := cli.WriteFileToVolume(vol, "/var/", "/var/foo", "bar\nbaz")
 	require.NoError(t, err)
 
-	out, err := cli.Run(&docker.RunOpts{
+	out, err := cli.Run(context.Background(), &docker.RunOpts{
 		Image:      "docker.io/library/busybox:1.33.1",
 		Entrypoint: []string{"cat"},
 		Cmd:        []string{"/var/foo"},
@@ -46,3 +48,29 @@ func TestGetFileFromVolume(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte("bar\nbaz"), fileBytes)
 }
+
+func TestReadTarFromVolume(t *testing.T) {
+	cli := docker.MustClient()
+
+	vol := cli.MustCreateVolume()
+	defer cli.MustRemoveVolumes(vol)
+
+	err := cli.WriteFileToVolume(vol, "/var/", "/var/foo", "bar\nbaz")
+	require.NoError(t, err)
+
+	archive := &bytes.Buffer{}
+	err = cli.ReadTarFromVolume(context.Background(), vol, "/var/", archive)
+	require.NoError(t, err)
+
+	restoredVol := cli.MustCreateVolume()
+	defer cli.MustRemoveVolumes(restoredVol)
+
+	err = cli.WriteTarToVolume(context.Background(), restoredVol, "/mnt/", "/mnt/", archive)
+	require.NoError(t, err)
+
+	fileReader, err := cli.GetFileFromVolume(restoredVol, "/mnt/", "/mnt/foo")
+	require.NoError(t, err)
+	fileBytes, err := ioutil.ReadAll(fileReader)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar\nbaz"), fileBytes)
+}
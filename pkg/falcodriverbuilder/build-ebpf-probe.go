@@ -1,6 +1,7 @@
 package falcodriverbuilder
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/thought-machine/falco-probes/internal/logging"
@@ -11,20 +12,28 @@ import (
 var log = logging.Logger
 
 // BuildEBPFProbe builds a Falco eBPF probe with the given falcoVersion, operatingsystem and kernelPackageName, returning the falcoDriverVersion and outProbePath.
+// The returned falcoDriverVersion is the operatingsystem.DriverVersionPath of the kernel package's machine, which the probe is organised under.
+// ErrUnsupportedFalcoVersion is returned if the falcoVersion does not support the kernel package.
 func BuildEBPFProbe(
+	ctx context.Context,
 	cli *docker.Client,
 	falcoVersion string,
 	os operatingsystem.OperatingSystem,
 	kernelPackage *operatingsystem.KernelPackage,
 ) (string, string, error) {
+	if !kernelPackage.SupportsFalcoVersion(falcoVersion) {
+		return "", "", fmt.Errorf("%w: %s requires falco >= %s", ErrUnsupportedFalcoVersion, kernelPackage.Name, kernelPackage.MinimumFalcoVersion())
+	}
+
 	log.Info().
 		Str("falco_version", falcoVersion).
+		Str("kernel_machine", kernelPackage.KernelMachine).
 		Msg("Building falco-driver-builder")
-	falcoDriverBuilderImage, err := BuildImage(cli, falcoVersion)
+	falcoDriverBuilderImage, err := BuildImageForMachine(cli, falcoVersion, kernelPackage.KernelMachine)
 	if err != nil {
 		return "", "", fmt.Errorf("could not build falco-driver-loader: %w", err)
 	}
-	falcoDriverVersion, err := GetDriverVersion(cli, falcoDriverBuilderImage)
+	falcoDriverVersion, err := GetDriverVersion(ctx, cli, falcoDriverBuilderImage)
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not get falco driver version")
 		return "", "", fmt.Errorf("could not get falco driver version: %w", err)
@@ -51,6 +60,7 @@ func BuildEBPFProbe(
 		Msg("Compiling Falco eBPF probe")
 	builtProbeVolume := cli.MustCreateVolume()
 	buildOut, err := cli.Run(
+		ctx,
 		&docker.RunOpts{
 			Image: falcoDriverBuilderImage,
 			Volumes: map[operatingsystem.Volume]string{
@@ -82,6 +92,7 @@ func BuildEBPFProbe(
 		return "", "", fmt.Errorf("could not extract probe from built probe volume: %w", err)
 	}
 
+	falcoDriverVersion = operatingsystem.DriverVersionPath(falcoDriverVersion, kernelPackage.KernelMachine)
 	outProbePath, err := WriteProbeToFile(falcoDriverVersion, builtProbePath, probeReader)
 	if err != nil {
 		return "", "", fmt.Errorf("could not write probe to file :%w", err)
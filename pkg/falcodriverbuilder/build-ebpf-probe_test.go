@@ -1,6 +1,7 @@
 package falcodriverbuilder_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/thought-machine/falco-probes/pkg/docker"
 	"github.com/thought-machine/falco-probes/pkg/falcodriverbuilder"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/resolver"
 )
 
@@ -18,7 +20,7 @@ func TestBuildEBPFProbe(t *testing.T) {
 		operatingSystemName string
 		kernelPackageName   string
 	}{
-	    // Amazon Linux 2
+		// Amazon Linux 2
 		{"0.33.0", "amazonlinux2", "4.14.200-155.322.amzn2"},
 		{"0.31.1", "amazonlinux2", "4.14.200-155.322.amzn2"},
 		{"0.29.0", "amazonlinux2", "4.14.200-155.322.amzn2"},
@@ -43,6 +45,11 @@ func TestBuildEBPFProbe(t *testing.T) {
 		{"0.33.0", "cos", "cos-97-16919-0-3"},
 		{"0.24.0", "cos", "cos-93-16623-0-5"},
 		{"0.33.0", "cos", "cos-93-16623-0-5"},
+
+		// aarch64 kernels, which are only supported by Falco >= 0.31.0
+		{"0.33.0", "amazonlinux2-aarch64", "4.14.200-155.322.amzn2.aarch64"},
+		{"0.31.1", "amazonlinux2-aarch64", "4.14.200-155.322.amzn2.aarch64"},
+		{"0.33.0", "cos-arm64", "cos-105-17412-101-24"},
 	}
 
 	cli := docker.MustClient()
@@ -53,11 +60,22 @@ func TestBuildEBPFProbe(t *testing.T) {
 			t.Parallel()
 			operatingSystem, err := resolver.OperatingSystem(cli, tt.operatingSystemName)
 			require.NoError(t, err)
-			kernelPackage, err := operatingSystem.GetKernelPackageByName(tt.kernelPackageName)
+			kernelPackage, err := operatingSystem.GetKernelPackageByName(context.Background(), tt.kernelPackageName)
 			require.NoError(t, err)
-			_, _, err = falcodriverbuilder.BuildEBPFProbe(cli, tt.falcoVersion, operatingSystem, kernelPackage)
+			_, _, err = falcodriverbuilder.BuildEBPFProbe(context.Background(), cli, tt.falcoVersion, operatingSystem, kernelPackage)
 			assert.NoError(t, err)
 		})
 
 	}
 }
+
+func TestBuildEBPFProbeUnsupportedFalcoVersion(t *testing.T) {
+	kernelPackage := &operatingsystem.KernelPackage{
+		OperatingSystem: "amazonlinux2",
+		Name:            "4.14.200-155.322.amzn2.aarch64",
+		KernelMachine:   operatingsystem.MachineAarch64,
+	}
+
+	_, _, err := falcodriverbuilder.BuildEBPFProbe(context.Background(), nil, "0.24.0", nil, kernelPackage)
+	assert.ErrorIs(t, err, falcodriverbuilder.ErrUnsupportedFalcoVersion)
+}
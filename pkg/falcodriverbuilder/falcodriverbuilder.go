@@ -1,6 +1,7 @@
 package falcodriverbuilder
 
 import (
+	"context"
 	// embed is used for including assets via Go 1.16
 	_ "embed"
 	"errors"
@@ -16,6 +17,7 @@ import (
 )
 
 // FalcoDriverBuilderDockerfile contains the Dockerfile contents for build a falco-driver-builder image.
+//
 //go:embed falco-driver-builder.Dockerfile
 var FalcoDriverBuilderDockerfile string
 
@@ -31,6 +33,9 @@ const (
 var (
 	// ErrCouldNotFindProbePathInOutput is returned when a probe could not be found in the output text.
 	ErrCouldNotFindProbePathInOutput = errors.New("could not find built probe path in output")
+	// ErrUnsupportedFalcoVersion is returned when a probe is built for a kernel package with a Falco version which does
+	// not support it, e.g. as it predates Falco's support for the kernel package's machine.
+	ErrUnsupportedFalcoVersion = errors.New("falco version does not support kernel package")
 )
 
 // BuildImage builds a falco-driver-builder docker image for the given Falco Version and returns the built image's FQN.
@@ -38,14 +43,25 @@ func BuildImage(
 	dockerClient *docker.Client,
 	falcoVersion string,
 ) (string, error) {
-	imageFQN := fmt.Sprintf("%s:%s", FalcoDriverBuilderRepository, falcoVersion)
+	return BuildImageForMachine(dockerClient, falcoVersion, operatingsystem.MachineX86_64)
+}
+
+// BuildImageForMachine builds a falco-driver-builder docker image for the given Falco Version which builds probes for
+// kernels of the given machine (`uname -m`), emulating the machine if needed, and returns the built image's FQN.
+func BuildImageForMachine(
+	dockerClient *docker.Client,
+	falcoVersion string,
+	kernelMachine string,
+) (string, error) {
+	imageFQN := fmt.Sprintf("%s:%s", FalcoDriverBuilderRepository, docker.TagForMachine(falcoVersion, kernelMachine))
 	err := dockerClient.Build(&docker.BuildOpts{
 		Dockerfile: FalcoDriverBuilderDockerfile,
 		BuildArgs: map[string]*string{
-			"FALCO_VERSION": docker.StrPtr(falcoVersion),
+			"FALCO_VERSION":  docker.StrPtr(falcoVersion),
 			"UBUNTU_VERSION": docker.StrPtr(UbuntuVersion),
 		},
-		Tags: []string{imageFQN},
+		Tags:     []string{imageFQN},
+		Platform: docker.PlatformForMachine(kernelMachine),
 	})
 	if err != nil {
 		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
@@ -85,8 +101,8 @@ func ExtractProbeFromVolume(
 }
 
 // GetDriverVersion returns the Falco Driver Version for the given falco-driver-builder image.
-func GetDriverVersion(dockerClient *docker.Client, image string) (string, error) {
-	out, err := dockerClient.Run(&docker.RunOpts{
+func GetDriverVersion(ctx context.Context, dockerClient *docker.Client, image string) (string, error) {
+	out, err := dockerClient.Run(ctx, &docker.RunOpts{
 		Image:      image,
 		Entrypoint: []string{"/bin/bash"},
 		Cmd:        []string{"-c", "cat /usr/bin/falco-driver-loader | grep DRIVER_VERSION= | cut -f2 -d\\\""},
@@ -1,6 +1,7 @@
 package falcodriverbuilder_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -105,7 +106,7 @@ func TestGetDriverVersion(t *testing.T) {
 			falcoDriverBuilderImg, err := falcodriverbuilder.BuildImage(cli, tt.FalcoVersion)
 			require.NoError(t, err)
 
-			actualFalcoDriverVersion, err := falcodriverbuilder.GetDriverVersion(cli, falcoDriverBuilderImg)
+			actualFalcoDriverVersion, err := falcodriverbuilder.GetDriverVersion(context.Background(), cli, falcoDriverBuilderImg)
 			require.NoError(t, err)
 			assert.Equal(t, tt.ExpectedFalcoDriverVersion, actualFalcoDriverVersion)
 		})
@@ -0,0 +1,91 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/thought-machine/falco-probes/internal/atomicfile"
+)
+
+const (
+	bodySuffix = ".body"
+	etagSuffix = ".etag"
+)
+
+// getCached returns the body of the response to a GET request for the given URL, which is read from the cache if the
+// ETag of the cached response is still valid, or cached otherwise if the response has an ETag.
+func (f *Fetcher) getCached(ctx context.Context, url string) (io.ReadCloser, error) {
+	path := f.cachePath(url)
+
+	header := http.Header{}
+	if etag, err := ioutil.ReadFile(path + etagSuffix); err == nil {
+		header.Set("If-None-Match", string(etag))
+	}
+
+	resp, err := f.do(ctx, http.MethodGet, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		closeBody(resp)
+		if body, err := os.Open(path + bodySuffix); err == nil {
+			return body, nil
+		}
+
+		// the cached body has been removed since its ETag was read, so fetch it again.
+		if resp, err = f.do(ctx, http.MethodGet, url, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.StatusCode == http.StatusNotModified {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	if err := store(path, etag, resp.Body); err != nil {
+		return nil, fmt.Errorf("could not cache %s: %w", url, err)
+	}
+
+	return os.Open(path + bodySuffix)
+}
+
+// cachePath returns the path, without suffix, which the response for the given URL is cached at.
+func (f *Fetcher) cachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+
+	return filepath.Join(f.CacheDir, key[:2], key)
+}
+
+// store caches the given body with the given ETag at the given path. The ETag is only written once the body is
+// complete, so that an incomplete body is never revalidated.
+func store(path string, etag string, body io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.Remove(path + etagSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := atomicfile.WriteFile(path+bodySuffix, func(w io.Writer) error {
+		_, err := io.Copy(w, body)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(path+etagSuffix, func(w io.Writer) error {
+		_, err := io.WriteString(w, etag)
+		return err
+	})
+}
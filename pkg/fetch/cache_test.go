@@ -0,0 +1,69 @@
+package fetch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCached(t *testing.T) {
+	etag := `"v1"`
+	bodies := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		bodies++
+		w.Header().Set("ETag", etag)
+		w.Write([]byte("body " + etag))
+	}))
+	defer server.Close()
+
+	f := newTestFetcher()
+	f.CacheDir = t.TempDir()
+
+	assertBody := func(expected string) {
+		body, err := f.Get(context.Background(), server.URL)
+		require.NoError(t, err)
+		defer body.Close()
+		bodyBytes, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(bodyBytes))
+	}
+
+	assertBody(`body "v1"`)
+	assertBody(`body "v1"`)
+	assert.Equal(t, 1, bodies)
+
+	etag = `"v2"`
+	assertBody(`body "v2"`)
+	assert.Equal(t, 2, bodies)
+}
+
+func TestGetCachedWithoutETag(t *testing.T) {
+	bodies := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies++
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	f := newTestFetcher()
+	f.CacheDir = t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		body, err := f.Get(context.Background(), server.URL)
+		require.NoError(t, err)
+		bodyBytes, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+		body.Close()
+		assert.Equal(t, "body", string(bodyBytes))
+	}
+	assert.Equal(t, 2, bodies)
+}
@@ -0,0 +1,278 @@
+// Package fetch fetches files over HTTP resiliently, retrying rate limited and failed requests with exponential
+// backoff and optionally caching responses on disk.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/thought-machine/falco-probes/internal/logging"
+)
+
+var log = logging.Logger
+
+const (
+	// DefaultTries is the default number of tries (including the original) of a request.
+	DefaultTries = 8
+	// DefaultBaseDelay is the default delay before the first retry, which doubles for every subsequent retry.
+	DefaultBaseDelay = 2 * time.Second
+	// DefaultMaxDelay is the default maximum delay between tries.
+	DefaultMaxDelay = 2 * time.Minute
+	// DefaultTimeout is the default time to wait for the response to a request, which does not limit reading its body
+	// so that large files can be streamed.
+	DefaultTimeout = 5 * time.Minute
+)
+
+// HTTPClient is the interface of the client which performs requests, which http.Client implements.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Fetcher fetches files over HTTP, retrying requests which are rate limited (429), fail with a 5XX response or whose
+// connection fails, waiting for the duration given by the response's Retry-After header or exponentially longer
+// durations with jitter between tries.
+type Fetcher struct {
+	// Client performs the requests.
+	Client HTTPClient
+	// Tries is the number of tries (including the original) of a request.
+	Tries int
+	// BaseDelay is the delay before the first retry, which doubles for every subsequent retry.
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between tries, including those given by Retry-After.
+	MaxDelay time.Duration
+	// CacheDir is the directory to cache responses with an ETag in, which are revalidated rather than fetched again.
+	// Responses are not cached if empty.
+	CacheDir string
+}
+
+// New returns a new Fetcher with the default options which does not cache responses.
+func New() *Fetcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = DefaultTimeout
+
+	return &Fetcher{
+		Client:    &http.Client{Transport: transport},
+		Tries:     DefaultTries,
+		BaseDelay: DefaultBaseDelay,
+		MaxDelay:  DefaultMaxDelay,
+	}
+}
+
+// StatusError is returned when a request does not get a 2XX response, after retrying it if needed.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("could not get 2XX response for %s: %s", e.URL, e.Status)
+}
+
+// IsNotFound returns whether the given error is a StatusError for a 404 response.
+func IsNotFound(err error) bool {
+	statusErr := &StatusError{}
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// Get returns the body of the response to a GET request for the given URL, which must be closed by the caller.
+func (f *Fetcher) Get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if f.CacheDir != "" {
+		return f.getCached(ctx, url)
+	}
+
+	resp, err := f.do(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// ReadAll returns the body of the response to a GET request for the given URL.
+func (f *Fetcher) ReadAll(ctx context.Context, url string) ([]byte, error) {
+	body, err := f.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", url, err)
+	}
+
+	return bodyBytes, nil
+}
+
+// Exists returns whether a HEAD request for the given URL gets a 2XX response, or an error if it is still rate limited
+// or failing after retrying it.
+func (f *Fetcher) Exists(ctx context.Context, url string) (bool, error) {
+	resp, err := f.do(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		statusErr := &StatusError{}
+		if errors.As(err, &statusErr) && !isRetryableStatus(statusErr.StatusCode) {
+			return false, nil
+		}
+		return false, err
+	}
+	closeBody(resp)
+
+	return true, nil
+}
+
+// do performs a request with the given method and headers for the given URL, retrying it if needed. It returns the
+// response if it is 2XX or 304 (Not Modified), or a StatusError otherwise.
+func (f *Fetcher) do(ctx context.Context, method string, url string, header http.Header) (*http.Response, error) {
+	for try := 1; ; try++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := f.Client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !isRetryableError(err) || try >= f.Tries {
+				return nil, err
+			}
+			if err := f.wait(ctx, url, try, f.backoff(try), err.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if (resp.StatusCode >= 200 && resp.StatusCode <= 299) || resp.StatusCode == http.StatusNotModified {
+			return resp, nil
+		}
+		closeBody(resp)
+
+		statusErr := &StatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+		if !isRetryableStatus(resp.StatusCode) || try >= f.Tries {
+			return nil, statusErr
+		}
+
+		delay, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			delay = f.backoff(try)
+		}
+		if err := f.wait(ctx, url, try, delay, resp.Status); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait logs that the given try of a request for the given URL failed for the given reason, and waits for the given
+// delay, limited to MaxDelay, before it is retried.
+func (f *Fetcher) wait(ctx context.Context, url string, try int, delay time.Duration, reason string) error {
+	if f.MaxDelay > 0 && delay > f.MaxDelay {
+		delay = f.MaxDelay
+	}
+
+	log.Warn().
+		Str("url", url).
+		Int("try", try).
+		Str("reason", reason).
+		Dur("delay", delay).
+		Msg("retrying request")
+
+	return sleep(ctx, delay)
+}
+
+var (
+	jitterMu sync.Mutex
+	jitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoff returns the delay before retrying after the given try, which is between half and all of BaseDelay doubled
+// for every previous retry.
+func (f *Fetcher) backoff(try int) time.Duration {
+	delay := f.BaseDelay
+	for i := 1; i < try && (f.MaxDelay <= 0 || delay < f.MaxDelay); i++ {
+		delay *= 2
+	}
+	if f.MaxDelay > 0 && delay > f.MaxDelay {
+		delay = f.MaxDelay
+	}
+	if delay < 2 {
+		return delay
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+
+	return delay/2 + time.Duration(jitter.Int63n(int64(delay/2)))
+}
+
+// retryAfter returns the delay given by the given Retry-After header value relative to the given time, and whether
+// there is one. See https://www.rfc-editor.org/rfc/rfc9110#field.retry-after.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// isRetryableStatus returns whether a request whose response has the given status code should be retried.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// isRetryableError returns whether a request which failed with the given error should be retried, i.e. whether its
+// connection was reset, closed early or timed out.
+func isRetryableError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// closeBody closes the given response's body, which may be nil when the response is mocked.
+func closeBody(resp *http.Response) {
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+}
+
+// sleep waits for the given duration, returning early with the context's error if the given context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+package fetch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+// newTestFetcher returns a Fetcher which retries quickly.
+func newTestFetcher() *fetch.Fetcher {
+	f := fetch.New()
+	f.Tries = 3
+	f.BaseDelay = time.Millisecond
+	f.MaxDelay = 2 * time.Second
+
+	return f
+}
+
+// newFlakyServer returns a server which responds to the first failures requests with the given status code (or by
+// closing the connection if 0) and with "ok" afterwards, along with the number of requests it has received.
+func newFlakyServer(t *testing.T, failures int32, statusCode int, header http.Header) (*httptest.Server, *int32) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > failures {
+			w.Write([]byte("ok"))
+			return
+		}
+
+		if statusCode == 0 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGetRetries(t *testing.T) {
+	var tests = []struct {
+		name       string
+		statusCode int
+	}{
+		{"rate limited", http.StatusTooManyRequests},
+		{"server error", http.StatusServiceUnavailable},
+		{"connection closed", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFlakyServer(t, 2, tt.statusCode, nil)
+
+			body, err := newTestFetcher().Get(context.Background(), server.URL)
+			require.NoError(t, err)
+			defer body.Close()
+
+			bodyBytes, err := ioutil.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, "ok", string(bodyBytes))
+			assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+		})
+	}
+}
+
+func TestGetGivesUp(t *testing.T) {
+	server, requests := newFlakyServer(t, 3, http.StatusTooManyRequests, nil)
+
+	_, err := newTestFetcher().Get(context.Background(), server.URL)
+
+	statusErr := &fetch.StatusError{}
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestGetDoesNotRetryNotFound(t *testing.T) {
+	server, requests := newFlakyServer(t, 1, http.StatusNotFound, nil)
+
+	_, err := newTestFetcher().Get(context.Background(), server.URL)
+
+	assert.True(t, fetch.IsNotFound(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestGetHonoursRetryAfter(t *testing.T) {
+	server, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+	start := time.Now()
+	body, err := newTestFetcher().Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	body.Close()
+
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestGetStopsWhenCancelled(t *testing.T) {
+	server, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := newTestFetcher().Get(ctx, server.URL)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestExists(t *testing.T) {
+	found, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+	notFound, _ := newFlakyServer(t, 1, http.StatusNotFound, nil)
+	rateLimited, _ := newFlakyServer(t, 3, http.StatusTooManyRequests, nil)
+
+	exists, err := newTestFetcher().Exists(context.Background(), found.URL)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = newTestFetcher().Exists(context.Background(), notFound.URL)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = newTestFetcher().Exists(context.Background(), rateLimited.URL)
+	assert.Error(t, err)
+}
+
+func TestReadAll(t *testing.T) {
+	server, _ := newFlakyServer(t, 1, http.StatusBadGateway, nil)
+
+	body, err := newTestFetcher().ReadAll(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+}
@@ -1,8 +1,8 @@
 package amazonlinux2
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"strings"
 
 	"github.com/thought-machine/falco-probes/pkg/docker"
@@ -15,32 +15,36 @@ var (
 )
 
 // NewKernelPackage returns a new hydrated example implementation operatingsystem.KernelPackage.
-func NewKernelPackage(dockerClient *docker.Client, name string) (*operatingsystem.KernelPackage, error) {
+// Kernel packages whose name is suffixed with `.aarch64` are downloaded for aarch64 under emulation.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, name string) (*operatingsystem.KernelPackage, error) {
 	kP := &operatingsystem.KernelPackage{
 		OperatingSystem: "amazonlinux2",
 		Name:            name,
 	}
 
-	if err := addSourcesAndConfiguration(dockerClient, kP); err != nil {
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP); err != nil {
 		return nil, err
 	}
 
-	if err := addOSRelease(dockerClient, kP); err != nil {
+	osRelease, err := dockerClient.GetOSRelease(ctx, amazonLinux2Image)
+	if err != nil {
 		return nil, err
 	}
+	kP.OSRelease = osRelease
 
-	if err := addKernelReleaseAndVersionAndMachine(dockerClient, kP); err != nil {
+	if err := addKernelReleaseAndVersionAndMachine(ctx, dockerClient, kP); err != nil {
 		return nil, err
 	}
 
 	return kP, nil
 }
 
-func addSourcesAndConfiguration(dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
 	kp.KernelConfiguration = dockerClient.MustCreateVolume()
 	kp.KernelSources = dockerClient.MustCreateVolume()
 
-	yumDownloaderImage, err := BuildYumDownloader(dockerClient)
+	version, kernelMachine := parsePackageName(kp.Name)
+	yumDownloaderImage, err := BuildYumDownloader(dockerClient, kernelMachine)
 	if err != nil {
 		return fmt.Errorf("could not build falco-driver-loader: %w", err)
 	}
@@ -53,10 +57,11 @@ rpm2cpio kernel-devel-%s.$(uname -m).rpm | cpio --extract --make-directories
 `
 
 	_, err = dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      yumDownloaderImage,
 			Entrypoint: []string{"/bin/bash"},
-			Cmd:        []string{"-c", fmt.Sprintf(script, kp.Name, kp.Name, kp.Name, kp.Name)},
+			Cmd:        []string{"-c", fmt.Sprintf(script, version, version, version, version)},
 			Volumes: map[operatingsystem.Volume]string{
 				kp.KernelSources:       "/usr/src/",
 				kp.KernelConfiguration: "/lib/modules/",
@@ -70,62 +75,33 @@ rpm2cpio kernel-devel-%s.$(uname -m).rpm | cpio --extract --make-directories
 	return nil
 }
 
-func addOSRelease(dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
-	osReleaseVol := dockerClient.MustCreateVolume()
-	_, err := dockerClient.Run(
-		&docker.RunOpts{
-			Image:      "docker.io/library/amazonlinux:2",
-			Entrypoint: []string{"cp"},
-			Cmd:        []string{"/etc/os-release", "/host/etc/os-release"},
-			Volumes: map[operatingsystem.Volume]string{
-				osReleaseVol: "/host/etc/",
-			},
-		},
-	)
-	if err != nil {
-		return err
-	}
-
-	fileReader, err := dockerClient.GetFileFromVolume(osReleaseVol, "/host/etc/", "/host/etc/os-release")
+func addKernelReleaseAndVersionAndMachine(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
+	kernelSrcPath, err := findKernelSrcPath(ctx, dockerClient, kp.KernelSources, kp.Name)
 	if err != nil {
 		return err
 	}
 
-	fileContents, err := ioutil.ReadAll(fileReader)
-	if err != nil {
-		return err
-	}
-
-	kp.OSRelease = operatingsystem.FileContents(fileContents)
-
-	return nil
-}
-
-func addKernelReleaseAndVersionAndMachine(dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
-	kernelSrcPath, err := findKernelSrcPath(dockerClient, kp.KernelSources, kp.Name)
-	if err != nil {
-		return err
-	}
-
-	kernelVersion, err := getKernelVersion(dockerClient, kp.KernelSources, kernelSrcPath)
+	kernelVersion, err := getKernelVersion(ctx, dockerClient, kp.KernelSources, kernelSrcPath)
 	if err != nil {
 		return err
 	}
 	kp.KernelVersion = kernelVersion
 
-	kernelMachine, err := getKernelMachine(dockerClient, kp.KernelSources, kernelSrcPath)
+	kernelMachine, err := getKernelMachine(ctx, dockerClient, kp.KernelSources, kernelSrcPath)
 	if err != nil {
 		return err
 	}
 	kp.KernelMachine = kernelMachine
 
-	kp.KernelRelease = kp.Name + "." + kp.KernelMachine
+	version, _ := parsePackageName(kp.Name)
+	kp.KernelRelease = version + "." + kp.KernelMachine
 
 	return nil
 }
 
-func findKernelSrcPath(dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, name string) (string, error) {
+func findKernelSrcPath(ctx context.Context, dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, name string) (string, error) {
 	out, err := dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      amazonLinux2Image,
 			Entrypoint: []string{"find"},
@@ -142,27 +118,9 @@ func findKernelSrcPath(dockerClient *docker.Client, kernelSrcsVol operatingsyste
 	return strings.TrimSpace(out), nil
 }
 
-func getKernelRelease(dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, kernelSrcPath string) (string, error) {
-	out, err := dockerClient.Run(
-		&docker.RunOpts{
-			Image:      falcoDriverLoaderImage,
-			Entrypoint: []string{"/bin/bash"},
-			Cmd:        []string{"-c", "make kernelrelease | tail -n1"},
-			Volumes: map[operatingsystem.Volume]string{
-				kernelSrcsVol: "/usr/src/",
-			},
-			WorkingDir: kernelSrcPath,
-		},
-	)
-	if err != nil {
-		return "", err
-	}
-
-	return strings.TrimSpace(out), nil
-}
-
-func getKernelVersion(dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, kernelSrcPath string) (string, error) {
+func getKernelVersion(ctx context.Context, dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, kernelSrcPath string) (string, error) {
 	out, err := dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      falcoDriverLoaderImage,
 			Entrypoint: []string{"/bin/bash"},
@@ -180,8 +138,9 @@ func getKernelVersion(dockerClient *docker.Client, kernelSrcsVol operatingsystem
 	return strings.TrimSpace(out), nil
 }
 
-func getKernelMachine(dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, kernelSrcPath string) (string, error) {
+func getKernelMachine(ctx context.Context, dockerClient *docker.Client, kernelSrcsVol operatingsystem.Volume, kernelSrcPath string) (string, error) {
 	out, err := dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      falcoDriverLoaderImage,
 			Entrypoint: []string{"/bin/bash"},
@@ -1,6 +1,7 @@
 package amazonlinux2_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,10 +19,11 @@ func TestGetKernelConfiguration(t *testing.T) {
 
 	// TODO: this will likely fail in the future when this package is removed from their repositories;
 	// 		 we should use a dynamic name and assert it to the best we can.
-	kp, err := amazonlinux2.NewKernelPackage(cli, "4.14.200-155.322.amzn2")
+	kp, err := amazonlinux2.NewKernelPackage(context.Background(), cli, "4.14.200-155.322.amzn2")
 	require.NoError(t, err)
 
 	out, err := cli.Run(
+		context.Background(),
 		&docker.RunOpts{
 			Image:      "docker.io/library/busybox:latest",
 			Entrypoint: []string{"ls"},
@@ -40,10 +42,11 @@ func TestGetKernelSources(t *testing.T) {
 
 	// TODO: this will likely fail in the future when this package is removed from their repositories;
 	// 		 we should use a dynamic name and assert it to the best we can.
-	kp, err := amazonlinux2.NewKernelPackage(cli, "4.14.200-155.322.amzn2")
+	kp, err := amazonlinux2.NewKernelPackage(context.Background(), cli, "4.14.200-155.322.amzn2")
 	require.NoError(t, err)
 
 	out, err := cli.Run(
+		context.Background(),
 		&docker.RunOpts{
 			Image:      "docker.io/library/busybox:latest",
 			Entrypoint: []string{"ls"},
@@ -1,6 +1,7 @@
 package amazonlinux2
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -10,36 +11,58 @@ import (
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 )
 
-// Name represents the name of this operating system
-const Name = "amazonlinux2"
+const (
+	// Name represents the name of this operating system
+	Name = "amazonlinux2"
+	// Aarch64Name represents the name of this operating system for aarch64 (AWS Graviton) kernels.
+	Aarch64Name = Name + "-" + operatingsystem.MachineAarch64
+)
 
 // AmazonLinux2 implements operatingsystem.OperatingSystem for the amazonlinux2.
 type AmazonLinux2 struct {
 	operatingsystem.OperatingSystem
 
 	dockerClient *docker.Client
+	// kernelMachine is the `uname -m` of the kernels to list.
+	kernelMachine string
 }
 
 // NewAmazonLinux2 returns a new amazonlinux2 implementation of operatingsystem.OperatingSystem.
 func NewAmazonLinux2(dockerClient *docker.Client) operatingsystem.OperatingSystem {
 	return &AmazonLinux2{
-		dockerClient: dockerClient,
+		dockerClient:  dockerClient,
+		kernelMachine: operatingsystem.MachineX86_64,
+	}
+}
+
+// NewAmazonLinux2Aarch64 returns a new amazonlinux2 implementation of operatingsystem.OperatingSystem for aarch64
+// kernels, which are listed and hydrated under emulation.
+func NewAmazonLinux2Aarch64(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &AmazonLinux2{
+		dockerClient:  dockerClient,
+		kernelMachine: operatingsystem.MachineAarch64,
 	}
 }
 
 // GetName implements operatingsystem.OperatingSystem.GetName for the amazonlinux2.
 func (s *AmazonLinux2) GetName() string {
+	if s.kernelMachine == operatingsystem.MachineAarch64 {
+		return Aarch64Name
+	}
+
 	return Name
 }
 
 // GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for the amazonlinux2.
-func (s *AmazonLinux2) GetKernelPackageNames() ([]string, error) {
-	yumDownloaderImage, err := BuildYumDownloader(s.dockerClient)
+// The names of aarch64 kernel packages are suffixed with `.aarch64` to distinguish them from x86_64 ones.
+func (s *AmazonLinux2) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	yumDownloaderImage, err := BuildYumDownloader(s.dockerClient, s.kernelMachine)
 	if err != nil {
 		return nil, fmt.Errorf("could not build falco-driver-loader: %w", err)
 	}
 
 	out, err := s.dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      yumDownloaderImage,
 			Entrypoint: []string{"bash"},
@@ -47,20 +70,53 @@ func (s *AmazonLinux2) GetKernelPackageNames() ([]string, error) {
 		},
 	)
 	if err != nil {
-		return []string{}, err
+		return nil, err
 	}
 
 	out = strings.TrimSpace(out)
-	packageNames := strings.Split(out, "\n")
+	versions := strings.Split(out, "\n")
 
-	packageNames = onlyEBPFCompatiblePackageNames(packageNames)
+	versions = onlyEBPFCompatiblePackageNames(versions)
 
-	return packageNames, nil
+	packageNames := []string{}
+	for _, version := range versions {
+		packageNames = append(packageNames, packageName(version, s.kernelMachine))
+	}
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		version, kernelMachine := parsePackageName(ref.Name)
+		ref.KernelRelease = version + "." + kernelMachine
+		ref.Architecture = kernelMachine
+	}), nil
 }
 
 // GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for the amazonlinux2.
-func (s *AmazonLinux2) GetKernelPackageByName(name string) (*operatingsystem.KernelPackage, error) {
-	return NewKernelPackage(s.dockerClient, name)
+// Kernel packages of the other machine are rejected as their name alone determines the machine they are hydrated for.
+func (s *AmazonLinux2) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	if _, kernelMachine := parsePackageName(name); kernelMachine != s.kernelMachine {
+		return nil, fmt.Errorf("%s is a %s kernel package, not a %s kernel package of %s", name, kernelMachine, s.kernelMachine, s.GetName())
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, name)
+}
+
+// packageName returns the kernel package name of the given kernel package version (e.g. `4.14.200-155.322.amzn2`) and
+// machine, i.e. the version for x86_64 kernels and `<version>.<machine>` otherwise.
+func packageName(version string, kernelMachine string) string {
+	if kernelMachine == operatingsystem.MachineX86_64 {
+		return version
+	}
+
+	return version + "." + kernelMachine
+}
+
+// parsePackageName returns the kernel package version and machine of the given kernel package name.
+func parsePackageName(name string) (string, string) {
+	if strings.HasSuffix(name, "."+operatingsystem.MachineAarch64) {
+		return strings.TrimSuffix(name, "."+operatingsystem.MachineAarch64), operatingsystem.MachineAarch64
+	}
+
+	return name, operatingsystem.MachineX86_64
 }
 
 func onlyEBPFCompatiblePackageNames(packageNames []string) []string {
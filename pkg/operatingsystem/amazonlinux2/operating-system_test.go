@@ -1,10 +1,13 @@
 package amazonlinux2_test
 
 import (
+	"context"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/amazonlinux2"
 )
 
@@ -12,7 +15,7 @@ func TestGetKernelPackageNames(t *testing.T) {
 	cli := docker.MustClient()
 	os := amazonlinux2.NewAmazonLinux2(cli)
 
-	res, err := os.GetKernelPackageNames()
+	res, err := os.GetKernelPackageNames(context.Background())
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, res)
@@ -25,7 +28,7 @@ func TestGetKernelPackageByName(t *testing.T) {
 
 	// TODO: this will likely fail in the future when this package is removed from their repositories;
 	// 		 we should use a dynamic name and assert it to the best we can.
-	res, err := os.GetKernelPackageByName("4.14.200-155.322.amzn2")
+	res, err := os.GetKernelPackageByName(context.Background(), "4.14.200-155.322.amzn2")
 	assert.NoError(t, err)
 
 	assert.Equal(t, "4.14.200-155.322.amzn2.x86_64", res.KernelRelease)
@@ -36,3 +39,35 @@ func TestGetKernelPackageByName(t *testing.T) {
 	assert.NotEmpty(t, res.KernelConfiguration)
 	assert.NotEmpty(t, res.KernelSources)
 }
+
+func TestGetKernelPackageNamesAarch64(t *testing.T) {
+	cli := docker.MustClient()
+	os := amazonlinux2.NewAmazonLinux2Aarch64(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, ref := range res {
+		assert.Regexp(t, regexp.MustCompile(`\.amzn2\.aarch64$`), ref.Name)
+		assert.Equal(t, ref.Name, ref.KernelRelease)
+		assert.Equal(t, "aarch64", ref.Architecture)
+	}
+}
+
+func TestGetKernelPackageByNameOtherMachine(t *testing.T) {
+	var tests = []struct {
+		os   operatingsystem.OperatingSystem
+		name string
+	}{
+		{amazonlinux2.NewAmazonLinux2(nil), "4.14.200-155.322.amzn2.aarch64"},
+		{amazonlinux2.NewAmazonLinux2Aarch64(nil), "4.14.200-155.322.amzn2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.os.GetName()+"-"+tt.name, func(t *testing.T) {
+			_, err := tt.os.GetKernelPackageByName(context.Background(), tt.name)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -24,12 +24,14 @@ RUN export REPOS="kernel-5.4 kernel-5.10" \
 // YumDownloaderRepository is the repository to build the yumdownloader image under.
 const YumDownloaderRepository = "docker.io/thoughtmachine/falco-yumdownloader"
 
-// BuildYumDownloader builds the yumdownloader docker image.
-func BuildYumDownloader(dockerClient *docker.Client) (string, error) {
-	imageFQN := fmt.Sprintf("%s:latest", YumDownloaderRepository)
+// BuildYumDownloader builds the yumdownloader docker image for the given machine (`uname -m`), which downloads the
+// RPM packages of that machine.
+func BuildYumDownloader(dockerClient *docker.Client, kernelMachine string) (string, error) {
+	imageFQN := fmt.Sprintf("%s:%s", YumDownloaderRepository, docker.TagForMachine("latest", kernelMachine))
 	err := dockerClient.Build(&docker.BuildOpts{
 		Dockerfile: YumDownloaderDockerfile,
 		Tags:       []string{imageFQN},
+		Platform:   docker.PlatformForMachine(kernelMachine),
 	})
 	if err != nil {
 		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
@@ -0,0 +1,32 @@
+package amazonlinux2023
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// DnfDownloaderDockerfile represents the contents of a Dockerfile to build the dnfdownloader image which downloads RPM
+// packages from Amazon Linux 2023's repositories. Amazon Linux 2023 locks its repositories to the release of the
+// image, so we always query the latest release (`--releasever=latest`) to find every available kernel.
+const DnfDownloaderDockerfile = `FROM amazonlinux:2023
+RUN dnf install -y cpio dnf-plugins-core \
+	&& dnf clean all
+`
+
+// DnfDownloaderRepository is the repository to build the dnfdownloader image under.
+const DnfDownloaderRepository = "docker.io/thoughtmachine/falco-dnfdownloader"
+
+// BuildDnfDownloader builds the dnfdownloader docker image.
+func BuildDnfDownloader(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:amazonlinux2023", DnfDownloaderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: DnfDownloaderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -0,0 +1,94 @@
+package amazonlinux2023
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+var amazonLinux2023Image = "docker.io/library/amazonlinux:2023"
+
+// NewKernelPackage returns a new hydrated amazonlinux2023 implementation of operatingsystem.KernelPackage.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, name string) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            name,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, amazonLinux2023Image)
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	if err := addKernelReleaseAndVersionAndMachine(ctx, dockerClient, kP); err != nil {
+		return nil, err
+	}
+
+	return kP, nil
+}
+
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	dnfDownloaderImage, err := BuildDnfDownloader(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build dnfdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+dnf --quiet --releasever=latest download kernel-%[1]s kernel-devel-%[1]s
+rpm2cpio kernel-%[1]s.$(uname -m).rpm | cpio --extract --make-directories
+rpm2cpio kernel-devel-%[1]s.$(uname -m).rpm | cpio --extract --make-directories
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      dnfDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.Name)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addKernelReleaseAndVersionAndMachine reads the kernel release, version and machine from the extracted kernel-devel
+// package.
+func addKernelReleaseAndVersionAndMachine(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kp.KernelSources)
+	if err != nil {
+		return err
+	}
+	kp.KernelRelease = kernelRelease
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kp.KernelSources, kp.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return err
+	}
+	kp.KernelVersion = kernelVersion
+
+	kernelMachine, err := dockerClient.GetGeneratedDefine(ctx, kp.KernelSources, kp.KernelRelease, "UTS_MACHINE")
+	if err != nil {
+		return err
+	}
+	kp.KernelMachine = kernelMachine
+
+	return nil
+}
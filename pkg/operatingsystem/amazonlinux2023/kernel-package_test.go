@@ -0,0 +1,41 @@
+package amazonlinux2023_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/amazonlinux2023"
+)
+
+func TestGetKernelSourcesAndConfiguration(t *testing.T) {
+	cli := docker.MustClient()
+
+	// Amazon Linux 2023 kernels are superseded often so we cannot hardcode a kernel package name.
+	names, err := amazonlinux2023.NewAmazonLinux2023(cli).GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	kp, err := amazonlinux2023.NewKernelPackage(context.Background(), cli, names[0].Name)
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/" + kp.KernelRelease + "/Makefile && " +
+				"ls /lib/modules/" + kp.KernelRelease + "/config && " +
+				"grep CONFIG_BPF= /lib/modules/" + kp.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
@@ -0,0 +1,79 @@
+package amazonlinux2023
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system
+const Name = "amazonlinux2023"
+
+// AmazonLinux2023 implements operatingsystem.OperatingSystem for the amazonlinux2023.
+type AmazonLinux2023 struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewAmazonLinux2023 returns a new amazonlinux2023 implementation of operatingsystem.OperatingSystem.
+func NewAmazonLinux2023(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &AmazonLinux2023{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for the amazonlinux2023.
+func (s *AmazonLinux2023) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for the amazonlinux2023.
+// Note that KernelPackageNames in this context are the `<version>-<release>` of the kernel-devel package,
+// e.g. `6.1.25-37.47.amzn2023`.
+func (s *AmazonLinux2023) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	dnfDownloaderImage, err := BuildDnfDownloader(s.dockerClient)
+	if err != nil {
+		return nil, fmt.Errorf("could not build dnfdownloader: %w", err)
+	}
+
+	out, err := s.dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      dnfDownloaderImage,
+			Entrypoint: []string{"bash"},
+			Cmd:        []string{"-c", "dnf --quiet --releasever=latest repoquery --queryformat '%{version}-%{release}' kernel-devel | sort -uV"},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return operatingsystem.NewKernelPackageRefs(parseKernelPackageNames(out), func(ref *operatingsystem.KernelPackageRef) {
+		ref.KernelRelease = ref.Name + ".x86_64"
+		ref.Architecture = "x86_64"
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for the amazonlinux2023.
+func (s *AmazonLinux2023) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, name)
+}
+
+// parseKernelPackageNames returns the Amazon Linux 2023 kernel package names from the given dnf output, ignoring any
+// other lines dnf may output (e.g. metadata refresh messages).
+func parseKernelPackageNames(out string) []string {
+	packageNames := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if !strings.HasSuffix(name, ".amzn2023") || !operatingsystem.IsEBPFCompatible(name) {
+			continue
+		}
+		packageNames = append(packageNames, name)
+	}
+
+	return packageNames
+}
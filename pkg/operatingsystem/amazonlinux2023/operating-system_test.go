@@ -0,0 +1,48 @@
+package amazonlinux2023_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/amazonlinux2023"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := amazonlinux2023.NewAmazonLinux2023(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.amzn2023$`), name)
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := amazonlinux2023.NewAmazonLinux2023(cli)
+
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	name := names[len(names)-1].Name
+
+	res, err := os.GetKernelPackageByName(context.Background(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "amazonlinux2023", res.OperatingSystem)
+	assert.Equal(t, name+".x86_64", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "VERSION_ID=\"2023\"")
+	assert.Contains(t, res.ProbeName(), "falco_amazonlinux2023_"+name+".x86_64_1")
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
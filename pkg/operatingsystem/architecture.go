@@ -0,0 +1,38 @@
+package operatingsystem
+
+import "strings"
+
+const (
+	// MachineX86_64 is the KernelMachine (`uname -m`) of 64-bit x86 kernels.
+	MachineX86_64 = "x86_64"
+	// MachineAarch64 is the KernelMachine (`uname -m`) of 64-bit Arm kernels.
+	MachineAarch64 = "aarch64"
+)
+
+// machineMinimumFalcoVersions are the oldest Falco versions which build probes for kernels of each machine, for the
+// machines not supported by every Falco version. Falco added support for arm64 in 0.31.0.
+var machineMinimumFalcoVersions = map[string]string{
+	MachineAarch64: "0.31.0",
+}
+
+// DriverVersionPath returns the path to organise probes built with the given Falco driver version for kernels of the
+// given machine under. Falco's probe names do not include the architecture, so probes for x86_64 kernels are
+// organised under the driver version as they always have been, and probes for other machines under
+// `<driver version>/<machine>` like Falco's own driver repository.
+func DriverVersionPath(driverVersion string, kernelMachine string) string {
+	if kernelMachine == "" || kernelMachine == MachineX86_64 {
+		return driverVersion
+	}
+
+	return driverVersion + "/" + kernelMachine
+}
+
+// ParseDriverVersionPath returns the Falco driver version and kernel machine of the given DriverVersionPath.
+func ParseDriverVersionPath(driverVersionPath string) (string, string) {
+	parts := strings.SplitN(driverVersionPath, "/", 2)
+	if len(parts) < 2 {
+		return driverVersionPath, MachineX86_64
+	}
+
+	return parts[0], parts[1]
+}
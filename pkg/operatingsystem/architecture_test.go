@@ -0,0 +1,33 @@
+package operatingsystem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+func TestDriverVersionPath(t *testing.T) {
+	var tests = []struct {
+		driverVersion string
+		kernelMachine string
+		expectedPath  string
+	}{
+		{"3.0.1+driver", "x86_64", "3.0.1+driver"},
+		{"3.0.1+driver", "", "3.0.1+driver"},
+		{"3.0.1+driver", "aarch64", "3.0.1+driver/aarch64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedPath, func(t *testing.T) {
+			path := operatingsystem.DriverVersionPath(tt.driverVersion, tt.kernelMachine)
+			assert.Equal(t, tt.expectedPath, path)
+
+			driverVersion, kernelMachine := operatingsystem.ParseDriverVersionPath(path)
+			assert.Equal(t, tt.driverVersion, driverVersion)
+			if tt.kernelMachine != "" {
+				assert.Equal(t, tt.kernelMachine, kernelMachine)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+// Package archive exports hydrated kernel packages to tar archives and imports them again, so that kernel packages
+// can be hydrated on a machine with network access and their probes built on another without it.
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const (
+	// MetadataName is the name of the kernel package's metadata in an archive, which is always its first entry.
+	MetadataName = "metadata.json"
+	// KernelConfigurationDir is the directory of an archive which contains the kernel package's KernelConfiguration.
+	KernelConfigurationDir = "kernel-configuration"
+	// KernelSourcesDir is the directory of an archive which contains the kernel package's KernelSources.
+	KernelSourcesDir = "kernel-sources"
+
+	// volumeMnt is where volumes are mounted when exporting and importing their contents.
+	volumeMnt = "/volume/"
+)
+
+// Metadata represents everything about a kernel package except for the contents of its volumes.
+type Metadata struct {
+	OperatingSystem string                       `json:"operating_system"`
+	Name            string                       `json:"name"`
+	KernelRelease   string                       `json:"kernel_release"`
+	KernelVersion   string                       `json:"kernel_version"`
+	KernelMachine   string                       `json:"kernel_machine"`
+	OSRelease       operatingsystem.FileContents `json:"os_release"`
+}
+
+// NewMetadata returns the Metadata of the given kernel package.
+func NewMetadata(kp *operatingsystem.KernelPackage) Metadata {
+	return Metadata{
+		OperatingSystem: kp.OperatingSystem,
+		Name:            kp.Name,
+		KernelRelease:   kp.KernelRelease,
+		KernelVersion:   kp.KernelVersion,
+		KernelMachine:   kp.KernelMachine,
+		OSRelease:       kp.OSRelease,
+	}
+}
+
+// KernelPackage returns the kernel package described by this Metadata with the given volumes.
+func (m Metadata) KernelPackage(kernelConfiguration operatingsystem.Volume, kernelSources operatingsystem.Volume) *operatingsystem.KernelPackage {
+	return &operatingsystem.KernelPackage{
+		OperatingSystem:     m.OperatingSystem,
+		Name:                m.Name,
+		KernelRelease:       m.KernelRelease,
+		KernelVersion:       m.KernelVersion,
+		KernelMachine:       m.KernelMachine,
+		OSRelease:           m.OSRelease,
+		KernelConfiguration: kernelConfiguration,
+		KernelSources:       kernelSources,
+	}
+}
+
+// Export writes the given kernel package to the given writer as a tar archive of its Metadata, followed by the
+// contents of its KernelConfiguration under KernelConfigurationDir and its KernelSources under KernelSourcesDir.
+func Export(ctx context.Context, cli *docker.Client, kp *operatingsystem.KernelPackage, w io.Writer) error {
+	tarWriter := tar.NewWriter(w)
+
+	metadataBytes, err := json.MarshalIndent(NewMetadata(kp), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:     MetadataName,
+		Mode:     0o644,
+		Size:     int64(len(metadataBytes)),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(metadataBytes); err != nil {
+		return err
+	}
+
+	if err := exportVolume(ctx, cli, kp.KernelConfiguration, KernelConfigurationDir, tarWriter); err != nil {
+		return fmt.Errorf("could not export kernel configuration: %w", err)
+	}
+	if err := exportVolume(ctx, cli, kp.KernelSources, KernelSourcesDir, tarWriter); err != nil {
+		return fmt.Errorf("could not export kernel sources: %w", err)
+	}
+
+	return tarWriter.Close()
+}
+
+// exportVolume writes the contents of the given volume to the given tar writer under the given directory.
+func exportVolume(ctx context.Context, cli *docker.Client, volume operatingsystem.Volume, dir string, tarWriter *tar.Writer) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(cli.ReadTarFromVolume(ctx, volume, volumeMnt, pw))
+	}()
+	defer pr.Close()
+
+	tarReader := tar.NewReader(pr)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(dir, header.Name)
+		if header.Typeflag == tar.TypeLink {
+			header.Linkname = path.Join(dir, header.Linkname)
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return err
+		}
+	}
+
+	// read the rest of the archive (i.e. its padding) so that any error reading it from docker is returned.
+	_, err := io.Copy(ioutil.Discard, pr)
+	return err
+}
+
+// Import returns the kernel package exported to the given archive, which may be compressed with gzip, with its
+// volumes restored into new volumes.
+func Import(ctx context.Context, cli *docker.Client, r io.Reader) (*operatingsystem.KernelPackage, error) {
+	r, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	tarReader := tar.NewReader(r)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", MetadataName, err)
+	}
+	if header.Name != MetadataName {
+		return nil, fmt.Errorf("expected %s as the first entry of the archive, found %s", MetadataName, header.Name)
+	}
+	metadata := Metadata{}
+	if err := json.NewDecoder(tarReader).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", MetadataName, err)
+	}
+
+	volumes := map[string]operatingsystem.Volume{}
+	removeVolumes := func() {
+		for _, volume := range volumes {
+			cli.MustRemoveVolumes(volume)
+		}
+	}
+
+	header, err = tarReader.Next()
+	for err == nil {
+		dir := strings.SplitN(path.Clean(header.Name), "/", 2)[0]
+		if dir != KernelConfigurationDir && dir != KernelSourcesDir {
+			removeVolumes()
+			return nil, fmt.Errorf("unexpected entry %s in archive", header.Name)
+		}
+		if _, ok := volumes[dir]; ok {
+			removeVolumes()
+			return nil, fmt.Errorf("entries of %s are not contiguous in archive", dir)
+		}
+
+		volumes[dir] = cli.MustCreateVolume()
+		header, err = importVolume(ctx, cli, volumes[dir], dir, header, tarReader)
+	}
+	if err != io.EOF {
+		removeVolumes()
+		return nil, err
+	}
+
+	// volumes which were empty when exported are not in the archive.
+	for _, dir := range []string{KernelConfigurationDir, KernelSourcesDir} {
+		if _, ok := volumes[dir]; !ok {
+			volumes[dir] = cli.MustCreateVolume()
+		}
+	}
+
+	return metadata.KernelPackage(volumes[KernelConfigurationDir], volumes[KernelSourcesDir]), nil
+}
+
+// importVolume writes the entries of the given tar reader under the given directory, starting with the given header,
+// to the given volume. It returns the header of the first entry which is not under the given directory.
+func importVolume(ctx context.Context, cli *docker.Client, volume operatingsystem.Volume, dir string, header *tar.Header, tarReader *tar.Reader) (*tar.Header, error) {
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		err := cli.WriteTarToVolume(ctx, volume, volumeMnt, volumeMnt, pr)
+		pr.CloseWithError(err)
+		errCh <- err
+	}()
+
+	header, err := copyDir(dir, header, tarReader, pw)
+	pw.CloseWithError(err)
+	if writeErr := <-errCh; writeErr != nil && err == nil {
+		err = writeErr
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return header, err
+}
+
+// copyDir writes the entries of the given tar reader under the given directory, starting with the given header, to
+// the given writer as a tar archive relative to the directory. It returns the header of the first entry which is not
+// under the given directory, or io.EOF at the end of the archive.
+func copyDir(dir string, header *tar.Header, tarReader *tar.Reader, w io.Writer) (*tar.Header, error) {
+	tarWriter := tar.NewWriter(w)
+
+	var err error
+	for ; err == nil; header, err = tarReader.Next() {
+		name, ok := relativeTo(dir, header.Name)
+		if !ok {
+			break
+		}
+
+		header.Name = name
+		if header.Typeflag == tar.TypeLink {
+			if header.Linkname, ok = relativeTo(dir, header.Linkname); !ok {
+				return nil, fmt.Errorf("hard link %s points outside of %s", header.Name, dir)
+			}
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if closeErr := tarWriter.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+
+	return header, err
+}
+
+// relativeTo returns the given archive path relative to the given directory, and whether it is within it.
+func relativeTo(dir string, name string) (string, bool) {
+	name = path.Clean(name)
+	if name == dir {
+		return ".", true
+	}
+	if !strings.HasPrefix(name, dir+"/") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(name, dir+"/"), true
+}
+
+// decompress returns the given reader, decompressing it if it is compressed with gzip.
+func decompress(r io.Reader) (io.Reader, error) {
+	bufReader := bufio.NewReader(r)
+	magic, err := bufReader.Peek(2)
+	if err != nil {
+		return nil, fmt.Errorf("could not read archive: %w", err)
+	}
+	if magic[0] != 0x1f || magic[1] != 0x8b {
+		return bufReader, nil
+	}
+
+	return gzip.NewReader(bufReader)
+}
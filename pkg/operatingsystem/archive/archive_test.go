@@ -0,0 +1,98 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+)
+
+func TestExportImport(t *testing.T) {
+	cli := docker.MustClient()
+
+	kernelConfiguration := cli.MustCreateVolume()
+	require.NoError(t, cli.WriteFileToVolume(kernelConfiguration, "/lib/modules/", "/lib/modules/config", "CONFIG_BPF=y"))
+	kernelSources := cli.MustCreateVolume()
+	require.NoError(t, cli.WriteFileToVolume(kernelSources, "/usr/src/", "/usr/src/Makefile", "VERSION = 5"))
+	defer cli.MustRemoveVolumes(kernelConfiguration, kernelSources)
+
+	exported := &operatingsystem.KernelPackage{
+		OperatingSystem:     "ubuntu",
+		Name:                "5.15.0-custom",
+		KernelRelease:       "5.15.0-custom",
+		KernelVersion:       "#7 SMP Mon Jan 2 00:00:00 UTC 2023",
+		KernelMachine:       operatingsystem.MachineX86_64,
+		OSRelease:           "ID=ubuntu\n",
+		KernelConfiguration: kernelConfiguration,
+		KernelSources:       kernelSources,
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, archive.Export(context.Background(), cli, exported, buf))
+
+	imported, err := archive.Import(context.Background(), cli, buf)
+	require.NoError(t, err)
+	defer cli.MustRemoveVolumes(imported.KernelConfiguration, imported.KernelSources)
+
+	assertFileInVolume(t, cli, imported.KernelConfiguration, "/lib/modules/", "/lib/modules/config", "CONFIG_BPF=y")
+	assertFileInVolume(t, cli, imported.KernelSources, "/usr/src/", "/usr/src/Makefile", "VERSION = 5")
+
+	assert.NotEqual(t, exported.KernelConfiguration, imported.KernelConfiguration)
+	assert.NotEqual(t, exported.KernelSources, imported.KernelSources)
+	imported.KernelConfiguration, imported.KernelSources = exported.KernelConfiguration, exported.KernelSources
+	assert.Equal(t, exported, imported)
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	var tests = []struct {
+		name    string
+		entries []string
+	}{
+		{"empty", []string{}},
+		{"missing metadata", []string{"kernel-sources/Makefile"}},
+		{"invalid metadata", []string{"metadata.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(buf)
+			for _, name := range tt.entries {
+				require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Typeflag: tar.TypeReg}))
+			}
+			require.NoError(t, tarWriter.Close())
+
+			_, err := archive.Import(context.Background(), nil, buf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestImportUnexpectedEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(buf)
+	metadata := []byte(`{"name": "5.15.0-custom"}`)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: archive.MetadataName, Mode: 0o644, Size: int64(len(metadata)), Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write(metadata)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "etc/passwd", Mode: 0o644, Typeflag: tar.TypeReg}))
+	require.NoError(t, tarWriter.Close())
+
+	_, err = archive.Import(context.Background(), nil, buf)
+	assert.EqualError(t, err, "unexpected entry etc/passwd in archive")
+}
+
+func assertFileInVolume(t *testing.T, cli *docker.Client, volume operatingsystem.Volume, volumeMnt string, path string, expected string) {
+	fileReader, err := cli.GetFileFromVolume(volume, volumeMnt, path)
+	require.NoError(t, err)
+	fileBytes, err := ioutil.ReadAll(fileReader)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(fileBytes))
+}
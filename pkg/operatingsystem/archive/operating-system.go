@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system.
+const Name = "archive"
+
+// ErrCannotListKernelPackages is returned when listing the kernel packages of the archive operating system, as they
+// are only known by their archives.
+var ErrCannotListKernelPackages = errors.New("archived kernel packages cannot be listed, give the path of their archive instead")
+
+// Archive implements operatingsystem.OperatingSystem for kernel packages which were exported to archives with Export,
+// e.g. on another machine. It does not access the network so can be used on air-gapped machines.
+type Archive struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewArchive returns a new archive implementation of operatingsystem.OperatingSystem.
+func NewArchive(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Archive{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for archive.
+func (s *Archive) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for archive. Archived kernel
+// packages cannot be listed so this always returns ErrCannotListKernelPackages.
+func (s *Archive) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	return nil, ErrCannotListKernelPackages
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for archive.
+// Note that the KernelPackageName in this context is the path of the kernel package's archive.
+func (s *Archive) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Import(ctx, s.dockerClient, f)
+}
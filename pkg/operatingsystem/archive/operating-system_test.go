@@ -0,0 +1,25 @@
+package archive_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	os := archive.NewArchive(nil)
+
+	_, err := os.GetKernelPackageNames(context.Background())
+
+	assert.ErrorIs(t, err, archive.ErrCannotListKernelPackages)
+}
+
+func TestGetKernelPackageByNameMissingArchive(t *testing.T) {
+	os := archive.NewArchive(nil)
+
+	_, err := os.GetKernelPackageByName(context.Background(), "/does/not/exist.tar")
+
+	assert.Error(t, err)
+}
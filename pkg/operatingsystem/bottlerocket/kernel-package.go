@@ -0,0 +1,98 @@
+package bottlerocket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// NewKernelPackage returns a new hydrated Bottlerocket implementation of operatingsystem.KernelPackage for the given
+// KernelKit.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, kernelKit *KernelKit) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            kernelKit.Name(),
+		KernelMachine:   arch,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, kernelKit); err != nil {
+		return nil, err
+	}
+
+	addOSRelease(kP, kernelKit)
+
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kP.KernelSources)
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelRelease = kernelRelease
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kP.KernelSources, kP.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelVersion = kernelVersion
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration downloads the kernel kit and extracts its kernel-devel archive into the sources volume at
+// `/usr/src/kernels/<kernel release>`, where falco-driver-builder expects to find it, as well as the conventional
+// `/lib/modules/<kernel release>/build` symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, kernelKit *KernelKit) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	kitDownloaderImage, err := BuildKitDownloader(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build kitdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+curl -sSfL -o kit.tar.xz %[1]s
+echo "%[2]s  kit.tar.xz" | sha256sum --check --quiet
+tar -xf kit.tar.xz
+mkdir devel
+tar -xf %[3]s/kernel-devel.tar.xz -C devel
+src="$(find devel -maxdepth 2 -name Makefile -printf '%%d %%h\n' | sort -n | head -n1 | cut -d' ' -f2)"
+release="$(grep -o 'UTS_RELEASE ".*"' "${src}/include/generated/utsrelease.h" | cut -d'"' -f2)"
+mkdir -p /usr/src/kernels "/lib/modules/${release}"
+cp -a "${src}" "/usr/src/kernels/${release}"
+ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+cp "${src}/.config" "/lib/modules/${release}/config"
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      kitDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kernelKit.URL, kernelKit.SHA256, kernelKit.DirName())},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addOSRelease mocks the `/etc/os-release` of a Bottlerocket host as Bottlerocket do not publish a container image of
+// their operating system. falco-driver-loader reads the `ID`, `VERSION_ID` and `VARIANT_ID` from it.
+func addOSRelease(kp *operatingsystem.KernelPackage, kernelKit *KernelKit) {
+	kp.OSRelease = operatingsystem.FileContents(fmt.Sprintf(`NAME=Bottlerocket
+ID=bottlerocket
+VERSION_ID=%[1]s
+PRETTY_NAME="Bottlerocket OS %[1]s"
+VARIANT_ID=%[2]s
+`, strings.TrimPrefix(kernelKit.Version, "v"), kernelKit.Variant))
+}
@@ -0,0 +1,40 @@
+package bottlerocket_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/bottlerocket"
+)
+
+func TestGetKernelSourcesAndConfiguration(t *testing.T) {
+	cli := docker.MustClient()
+
+	kernelKits, err := bottlerocket.ListKernelKits(context.Background(), bottlerocket.RepositoryURL, bottlerocket.TargetsURL, []string{"aws-k8s-1.24"})
+	require.NoError(t, err)
+	require.Contains(t, kernelKits, "aws-k8s-1.24-v1.11.1")
+
+	kp, err := bottlerocket.NewKernelPackage(context.Background(), cli, kernelKits["aws-k8s-1.24-v1.11.1"])
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/" + kp.KernelRelease + "/Makefile && " +
+				"ls /lib/modules/" + kp.KernelRelease + "/build && " +
+				"grep CONFIG_BPF= /lib/modules/" + kp.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
@@ -0,0 +1,32 @@
+package bottlerocket
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// KitDownloaderDockerfile represents the contents of a Dockerfile to build the kitdownloader image which downloads and
+// extracts Bottlerocket kernel kits.
+const KitDownloaderDockerfile = `FROM ubuntu:22.04
+RUN apt-get update \
+	&& apt-get install -y ca-certificates curl xz-utils \
+	&& rm -rf /var/lib/apt/lists/*
+`
+
+// KitDownloaderRepository is the repository to build the kitdownloader image under.
+const KitDownloaderRepository = "docker.io/thoughtmachine/falco-kitdownloader"
+
+// BuildKitDownloader builds the kitdownloader docker image.
+func BuildKitDownloader(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:latest", KitDownloaderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: KitDownloaderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -0,0 +1,88 @@
+package bottlerocket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system
+const Name = "bottlerocket"
+
+// Bottlerocket implements operatingsystem.OperatingSystem for Bottlerocket.
+type Bottlerocket struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+
+	// kernelKits caches the listing of Bottlerocket's TUF repositories as the kernel package name alone is not enough
+	// to locate its kernel kit.
+	kernelKits     map[string]*KernelKit
+	kernelKitsLock sync.Mutex
+}
+
+// NewBottlerocket returns a new Bottlerocket implementation of operatingsystem.OperatingSystem.
+func NewBottlerocket(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Bottlerocket{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for bottlerocket.
+func (s *Bottlerocket) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for bottlerocket.
+// Note that KernelPackageNames in this context are variant releases, e.g. `aws-k8s-1.24-v1.11.1`.
+func (s *Bottlerocket) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	kernelKits, err := s.getKernelKits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packageNames := []string{}
+	for name := range kernelKits {
+		packageNames = append(packageNames, name)
+	}
+	sort.Strings(packageNames)
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.Architecture = arch
+		ref.Repository = kernelKits[ref.Name].Variant
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for bottlerocket.
+func (s *Bottlerocket) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	kernelKits, err := s.getKernelKits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kernelKit, ok := kernelKits[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find kernel kit for %s in %s", name, RepositoryURL)
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, kernelKit)
+}
+
+func (s *Bottlerocket) getKernelKits(ctx context.Context) (map[string]*KernelKit, error) {
+	s.kernelKitsLock.Lock()
+	defer s.kernelKitsLock.Unlock()
+
+	if s.kernelKits == nil {
+		kernelKits, err := ListKernelKits(ctx, RepositoryURL, TargetsURL, Variants)
+		if err != nil {
+			return nil, fmt.Errorf("could not list kernel kits: %w", err)
+		}
+		s.kernelKits = kernelKits
+	}
+
+	return s.kernelKits, nil
+}
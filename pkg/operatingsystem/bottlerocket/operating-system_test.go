@@ -0,0 +1,44 @@
+package bottlerocket_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/bottlerocket"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := bottlerocket.NewBottlerocket(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^aws-(ecs|k8s)-[0-9.]+-v\d+\.\d+\.\d+$`), name)
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := bottlerocket.NewBottlerocket(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "aws-k8s-1.24-v1.11.1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bottlerocket", res.OperatingSystem)
+	assert.Regexp(t, regexp.MustCompile(`^5\.15\.\d+$`), res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=bottlerocket")
+	assert.Contains(t, res.OSRelease, "VERSION_ID=1.11.1")
+	assert.Equal(t, "falco_bottlerocket_"+res.KernelRelease+"_1_1.11.1-aws", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
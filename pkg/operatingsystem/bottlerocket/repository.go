@@ -0,0 +1,160 @@
+package bottlerocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// RepositoryURL is the base URL of Bottlerocket's TUF repositories, which hold the metadata for every variant's
+	// releases at `<variant>/<arch>/`.
+	RepositoryURL = "https://updates.bottlerocket.aws/2020-07-07/"
+	// TargetsURL is where the targets (i.e. files) described by Bottlerocket's TUF repositories are served from.
+	TargetsURL = "https://updates.bottlerocket.aws/targets/"
+
+	arch = "x86_64"
+)
+
+// Variants are the Bottlerocket variants whose kernel kits are listed. Variants ship different kernels so each should
+// be listed, though in practice releases of several variants share a kernel.
+var Variants = []string{
+	"aws-ecs-1",
+	"aws-ecs-2",
+	"aws-k8s-1.23",
+	"aws-k8s-1.24",
+	"aws-k8s-1.25",
+	"aws-k8s-1.26",
+	"aws-k8s-1.27",
+	"aws-k8s-1.28",
+}
+
+// kernelKitTargetRe matches the name of a kernel kit target, e.g.
+// `aws-k8s-1.24-x86_64-kmod-kit-v1.11.1.tar.xz` -> aws-k8s-1.24, v1.11.1.
+var kernelKitTargetRe = regexp.MustCompile(`^(.+)-` + arch + `-kmod-kit-(v[0-9]+\.[0-9]+\.[0-9]+)\.tar\.xz$`)
+
+// KernelKit represents the kernel kit of a release of a Bottlerocket variant, which is an archive containing the
+// kernel-devel archive and toolchain used to build out-of-tree kernel modules for that release.
+type KernelKit struct {
+	// Variant is the Bottlerocket variant the kernel kit is for, e.g. `aws-k8s-1.24`.
+	Variant string
+	// Version is the Bottlerocket release the kernel kit is for, e.g. `v1.11.1`.
+	Version string
+	// SHA256 is the expected SHA-256 hash of the kernel kit, as recorded in the TUF repository.
+	SHA256 string
+	// URL is the URL to download the kernel kit from.
+	URL string
+}
+
+// Name returns the kernel package name of the KernelKit, e.g. `aws-k8s-1.24-v1.11.1`.
+func (kk *KernelKit) Name() string {
+	return kk.Variant + "-" + kk.Version
+}
+
+// DirName returns the name of the directory the kernel kit archive extracts to, e.g.
+// `aws-k8s-1.24-x86_64-kmod-kit-v1.11.1`.
+func (kk *KernelKit) DirName() string {
+	return fmt.Sprintf("%s-%s-kmod-kit-%s", kk.Variant, arch, kk.Version)
+}
+
+type tufMeta struct {
+	Signed struct {
+		Meta map[string]struct {
+			Version int `json:"version"`
+		} `json:"meta"`
+	} `json:"signed"`
+}
+
+type tufTargets struct {
+	Signed struct {
+		Targets map[string]struct {
+			Hashes struct {
+				SHA256 string `json:"sha256"`
+			} `json:"hashes"`
+		} `json:"targets"`
+	} `json:"signed"`
+}
+
+// ListKernelKits returns the kernel kits of the given variants available in the TUF repositories at the given URLs,
+// keyed by kernel package name. The TUF metadata is only used to enumerate the kernel kits and their hashes; the
+// metadata's signatures are not verified.
+func ListKernelKits(ctx context.Context, repositoryURL string, targetsURL string, variants []string) (map[string]*KernelKit, error) {
+	fetcher := fetch.New()
+
+	kernelKits := map[string]*KernelKit{}
+	for _, variant := range variants {
+		metadataURL := fmt.Sprintf("%s%s/%s/", repositoryURL, variant, arch)
+
+		timestamp := &tufMeta{}
+		if err := getJSON(ctx, fetcher, metadataURL+"timestamp.json", timestamp); err != nil {
+			return nil, err
+		}
+
+		snapshot := &tufMeta{}
+		snapshotURL := fmt.Sprintf("%s%d.snapshot.json", metadataURL, timestamp.Signed.Meta["snapshot.json"].Version)
+		if err := getJSON(ctx, fetcher, snapshotURL, snapshot); err != nil {
+			return nil, err
+		}
+
+		targetsMetadataURL := fmt.Sprintf("%s%d.targets.json", metadataURL, snapshot.Signed.Meta["targets.json"].Version)
+		resp, err := fetcher.ReadAll(ctx, targetsMetadataURL)
+		if err != nil {
+			return nil, err
+		}
+
+		variantKernelKits, err := ParseKernelKits(variant, targetsURL, resp)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", targetsMetadataURL, err)
+		}
+
+		for name, kernelKit := range variantKernelKits {
+			kernelKits[name] = kernelKit
+		}
+	}
+
+	return kernelKits, nil
+}
+
+// ParseKernelKits returns the kernel kits of the given variant described by the given TUF targets metadata, keyed by
+// kernel package name.
+func ParseKernelKits(variant string, targetsURL string, targetsMetadata []byte) (map[string]*KernelKit, error) {
+	targets := &tufTargets{}
+	if err := json.Unmarshal(targetsMetadata, targets); err != nil {
+		return nil, err
+	}
+
+	kernelKits := map[string]*KernelKit{}
+	for target, meta := range targets.Signed.Targets {
+		matches := kernelKitTargetRe.FindStringSubmatch(target)
+		if matches == nil || matches[1] != variant {
+			continue
+		}
+
+		kernelKit := &KernelKit{
+			Variant: variant,
+			Version: matches[2],
+			SHA256:  meta.Hashes.SHA256,
+			// Bottlerocket's TUF repositories use consistent snapshots, so targets are served prefixed by their hash.
+			URL: fmt.Sprintf("%s%s.%s", targetsURL, meta.Hashes.SHA256, target),
+		}
+		kernelKits[kernelKit.Name()] = kernelKit
+	}
+
+	return kernelKits, nil
+}
+
+func getJSON(ctx context.Context, fetcher *fetch.Fetcher, url string, v interface{}) error {
+	body, err := fetcher.ReadAll(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("could not parse %s: %w", url, err)
+	}
+
+	return nil
+}
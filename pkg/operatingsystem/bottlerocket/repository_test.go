@@ -0,0 +1,103 @@
+package bottlerocket_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/bottlerocket"
+)
+
+// Excerpt of https://updates.bottlerocket.aws/2020-07-07/aws-k8s-1.24/x86_64/<version>.targets.json
+const testTargetsMetadata = `{
+  "signed": {
+    "_type": "targets",
+    "spec_version": "1.0.0",
+    "version": 1680206409,
+    "targets": {
+      "bottlerocket-aws-k8s-1.24-x86_64-1.11.1-104f8e0f-root.ext4.lz4": {
+        "length": 282353548,
+        "hashes": {"sha256": "0ab5b9d7b8f4bb0a5e1c8c0d1a6bb8c9c2b8e5ad6b1b0e4e1d6ba3d4b6b1a9a4"}
+      },
+      "aws-k8s-1.24-x86_64-kmod-kit-v1.11.1.tar.xz": {
+        "length": 42468012,
+        "hashes": {"sha256": "5d7f4b6c1c8e2e4b4a0f5b1f0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d"}
+      },
+      "aws-k8s-1.24-x86_64-kmod-kit-v1.12.0.tar.xz": {
+        "length": 42500123,
+        "hashes": {"sha256": "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d"}
+      },
+      "aws-k8s-1.24-aarch64-kmod-kit-v1.12.0.tar.xz": {
+        "length": 41500123,
+        "hashes": {"sha256": "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b"}
+      }
+    }
+  },
+  "signatures": []
+}`
+
+func TestParseKernelKits(t *testing.T) {
+	kernelKits, err := bottlerocket.ParseKernelKits("aws-k8s-1.24", bottlerocket.TargetsURL, []byte(testTargetsMetadata))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]*bottlerocket.KernelKit{
+		"aws-k8s-1.24-v1.11.1": {
+			Variant: "aws-k8s-1.24",
+			Version: "v1.11.1",
+			SHA256:  "5d7f4b6c1c8e2e4b4a0f5b1f0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d",
+			URL:     bottlerocket.TargetsURL + "5d7f4b6c1c8e2e4b4a0f5b1f0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d.aws-k8s-1.24-x86_64-kmod-kit-v1.11.1.tar.xz",
+		},
+		"aws-k8s-1.24-v1.12.0": {
+			Variant: "aws-k8s-1.24",
+			Version: "v1.12.0",
+			SHA256:  "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d",
+			URL:     bottlerocket.TargetsURL + "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d.aws-k8s-1.24-x86_64-kmod-kit-v1.12.0.tar.xz",
+		},
+	}, kernelKits)
+}
+
+func TestParseKernelKitsOtherVariant(t *testing.T) {
+	kernelKits, err := bottlerocket.ParseKernelKits("aws-k8s-1.23", bottlerocket.TargetsURL, []byte(testTargetsMetadata))
+	require.NoError(t, err)
+
+	assert.Empty(t, kernelKits)
+}
+
+func TestKernelKitDirName(t *testing.T) {
+	kernelKit := &bottlerocket.KernelKit{Variant: "aws-ecs-1", Version: "v1.11.1"}
+
+	assert.Equal(t, "aws-ecs-1-v1.11.1", kernelKit.Name())
+	assert.Equal(t, "aws-ecs-1-x86_64-kmod-kit-v1.11.1", kernelKit.DirName())
+}
+
+func TestListKernelKits(t *testing.T) {
+	mux := http.NewServeMux()
+	// variants of the same family ship different kernels, so the kernel kits of all of them are listed.
+	for _, variant := range []string{"aws-k8s-1.23", "aws-k8s-1.24"} {
+		variant := variant
+		mux.HandleFunc("/repository/"+variant+"/x86_64/timestamp.json", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"signed": {"meta": {"snapshot.json": {"version": 2}}}}`))
+		})
+		mux.HandleFunc("/repository/"+variant+"/x86_64/2.snapshot.json", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"signed": {"meta": {"targets.json": {"version": 3}}}}`))
+		})
+		mux.HandleFunc("/repository/"+variant+"/x86_64/3.targets.json", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"signed": {"targets": {"%s-x86_64-kmod-kit-v1.13.0.tar.xz": {"hashes": {"sha256": "abc"}}}}}`, variant)
+		})
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	kernelKits, err := bottlerocket.ListKernelKits(context.Background(), server.URL+"/repository/", server.URL+"/targets/", []string{"aws-k8s-1.23", "aws-k8s-1.24"})
+	require.NoError(t, err)
+
+	names := []string{}
+	for name := range kernelKits {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"aws-k8s-1.23-v1.13.0", "aws-k8s-1.24-v1.13.0"}, names)
+}
@@ -0,0 +1,233 @@
+// Package cache persists hydrated kernel packages on disk so that they can be reused across runs rather than
+// downloading and extracting their kernel sources and configuration again.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/thought-machine/falco-probes/internal/atomicfile"
+	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+)
+
+var log = logging.Logger
+
+// volumeMnt is where volumes are mounted when archiving and restoring their contents.
+const volumeMnt = "/volume/"
+
+// Opts represents the options for caching hydrated kernel packages.
+type Opts struct {
+	Dir string `long:"dir" description:"The directory to cache hydrated kernel packages in across runs (default: no caching)" env:"KERNEL_PACKAGE_CACHE_DIR"`
+}
+
+// OperatingSystem implements operatingsystem.OperatingSystem by returning kernel packages from the cache when they
+// have been hydrated before, and caching the kernel packages hydrated by the wrapped operating system otherwise.
+type OperatingSystem struct {
+	operatingsystem.OperatingSystem
+	dockerClient *docker.Client
+	dir          string
+}
+
+// Wrap returns the given operating system wrapped with a cache in the directory given in opts, or the given operating
+// system as-is if no directory is given.
+func Wrap(opts *Opts, dockerClient *docker.Client, operatingSystem operatingsystem.OperatingSystem) operatingsystem.OperatingSystem {
+	if opts.Dir == "" {
+		return operatingSystem
+	}
+
+	return &OperatingSystem{
+		OperatingSystem: operatingSystem,
+		dockerClient:    dockerClient,
+		dir:             opts.Dir,
+	}
+}
+
+// entry represents a cached kernel package, its volumes are stored as tar archives named after their SHA-256 digest
+// so that identical volumes (e.g. empty ones) are only stored once.
+type entry struct {
+	archive.Metadata
+	KernelConfiguration string `json:"kernel_configuration"`
+	KernelSources       string `json:"kernel_sources"`
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName.
+func (c *OperatingSystem) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	entryPath := c.entryPath(name)
+
+	kp, err := c.load(ctx, entryPath)
+	if err == nil {
+		log.Info().
+			Str("operating_system", c.GetName()).
+			Str("kernel_package", name).
+			Msg("using cached kernel package")
+		return kp, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).
+			Str("operating_system", c.GetName()).
+			Str("kernel_package", name).
+			Msg("could not use cached kernel package, hydrating it again")
+	}
+
+	kp, err = c.OperatingSystem.GetKernelPackageByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.store(ctx, entryPath, kp); err != nil {
+		log.Warn().Err(err).
+			Str("operating_system", c.GetName()).
+			Str("kernel_package", name).
+			Msg("could not cache kernel package")
+	}
+
+	return kp, nil
+}
+
+// entryPath returns the path of the cache entry for the given kernel package of this operating system, which also
+// depends on the operating system's configuration if it implements operatingsystem.CacheKeyer.
+func (c *OperatingSystem) entryPath(name string) string {
+	id := c.GetName() + "\x00" + name
+	if cacheKeyer, ok := c.OperatingSystem.(operatingsystem.CacheKeyer); ok {
+		id += "\x00" + cacheKeyer.CacheKey()
+	}
+
+	sum := sha256.Sum256([]byte(id))
+	key := hex.EncodeToString(sum[:])
+
+	return filepath.Join(c.dir, "kernel-packages", key[:2], key+".json")
+}
+
+// blobPath returns the path of the archive with the given digest.
+func (c *OperatingSystem) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", "sha256", digest[:2], digest+".tar")
+}
+
+// load returns the kernel package cached at the given path with its volumes restored from the cache.
+func (c *OperatingSystem) load(ctx context.Context, entryPath string) (*operatingsystem.KernelPackage, error) {
+	entryBytes, err := ioutil.ReadFile(entryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &entry{}
+	if err := json.Unmarshal(entryBytes, e); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", entryPath, err)
+	}
+
+	kernelConfiguration, err := c.restoreVolume(ctx, e.KernelConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("could not restore kernel configuration: %w", err)
+	}
+
+	kernelSources, err := c.restoreVolume(ctx, e.KernelSources)
+	if err != nil {
+		c.dockerClient.MustRemoveVolumes(kernelConfiguration)
+		return nil, fmt.Errorf("could not restore kernel sources: %w", err)
+	}
+
+	return e.KernelPackage(kernelConfiguration, kernelSources), nil
+}
+
+// restoreVolume returns a new volume with the contents of the archive with the given digest.
+func (c *OperatingSystem) restoreVolume(ctx context.Context, digest string) (operatingsystem.Volume, error) {
+	if len(digest) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid digest '%s'", digest)
+	}
+
+	archive, err := os.Open(c.blobPath(digest))
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	volume := c.dockerClient.MustCreateVolume()
+	h := sha256.New()
+	if err := c.dockerClient.WriteTarToVolume(ctx, volume, volumeMnt, volumeMnt, io.TeeReader(archive, h)); err != nil {
+		c.dockerClient.MustRemoveVolumes(volume)
+		return "", err
+	}
+	// docker may stop reading at the end of the archive rather than the end of the file.
+	if _, err := io.Copy(h, archive); err != nil {
+		c.dockerClient.MustRemoveVolumes(volume)
+		return "", err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != digest {
+		c.dockerClient.MustRemoveVolumes(volume)
+		return "", fmt.Errorf("archive %s has digest %s", c.blobPath(digest), actual)
+	}
+
+	return volume, nil
+}
+
+// store caches the given kernel package at the given path.
+func (c *OperatingSystem) store(ctx context.Context, entryPath string, kp *operatingsystem.KernelPackage) error {
+	kernelConfiguration, err := c.storeVolume(ctx, kp.KernelConfiguration)
+	if err != nil {
+		return fmt.Errorf("could not cache kernel configuration: %w", err)
+	}
+
+	kernelSources, err := c.storeVolume(ctx, kp.KernelSources)
+	if err != nil {
+		return fmt.Errorf("could not cache kernel sources: %w", err)
+	}
+
+	entryBytes, err := json.MarshalIndent(&entry{
+		Metadata:            archive.NewMetadata(kp),
+		KernelConfiguration: kernelConfiguration,
+		KernelSources:       kernelSources,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(entryPath, func(w io.Writer) error {
+		_, err := w.Write(entryBytes)
+		return err
+	})
+}
+
+// storeVolume caches the contents of the given volume and returns the digest of the archive they are cached as.
+func (c *OperatingSystem) storeVolume(ctx context.Context, volume operatingsystem.Volume) (string, error) {
+	tmpDir := filepath.Join(c.dir, "tmp")
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return "", err
+	}
+
+	archive, err := ioutil.TempFile(tmpDir, "blob-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	h := sha256.New()
+	if err := c.dockerClient.ReadTarFromVolume(ctx, volume, volumeMnt, io.MultiWriter(archive, h)); err != nil {
+		return "", err
+	}
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	blobPath := c.blobPath(digest)
+	if _, err := os.Stat(blobPath); err == nil {
+		return digest, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0o755); err != nil {
+		return "", err
+	}
+
+	return digest, os.Rename(archive.Name(), blobPath)
+}
@@ -0,0 +1,80 @@
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cache"
+)
+
+// fakeOperatingSystem hydrates kernel packages with a kernel configuration containing a single file, counting how
+// many times it does so.
+type fakeOperatingSystem struct {
+	dockerClient *docker.Client
+	hydrations   int
+}
+
+func (os *fakeOperatingSystem) GetName() string {
+	return "fake"
+}
+
+func (os *fakeOperatingSystem) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	return operatingsystem.NewKernelPackageRefs([]string{"5.10.0"}, nil), nil
+}
+
+func (os *fakeOperatingSystem) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	os.hydrations++
+
+	kernelConfiguration := os.dockerClient.MustCreateVolume()
+	if err := os.dockerClient.WriteFileToVolume(kernelConfiguration, "/lib/modules/", "/lib/modules/config", "CONFIG_BPF=y"); err != nil {
+		return nil, err
+	}
+
+	return &operatingsystem.KernelPackage{
+		OperatingSystem:     "fake",
+		Name:                name,
+		KernelRelease:       name,
+		KernelVersion:       "#1 SMP",
+		KernelMachine:       operatingsystem.MachineX86_64,
+		OSRelease:           "ID=fake",
+		KernelConfiguration: kernelConfiguration,
+		KernelSources:       os.dockerClient.MustCreateVolume(),
+	}, nil
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	fake := &fakeOperatingSystem{dockerClient: cli}
+	cached := cache.Wrap(&cache.Opts{Dir: t.TempDir()}, cli, fake)
+
+	hydrated, err := cached.GetKernelPackageByName(context.Background(), "5.10.0")
+	require.NoError(t, err)
+	defer cli.MustRemoveVolumes(hydrated.KernelConfiguration, hydrated.KernelSources)
+
+	restored, err := cached.GetKernelPackageByName(context.Background(), "5.10.0")
+	require.NoError(t, err)
+	defer cli.MustRemoveVolumes(restored.KernelConfiguration, restored.KernelSources)
+
+	assert.Equal(t, 1, fake.hydrations)
+	assert.NotEqual(t, hydrated.KernelConfiguration, restored.KernelConfiguration)
+
+	fileReader, err := cli.GetFileFromVolume(restored.KernelConfiguration, "/lib/modules/", "/lib/modules/config")
+	require.NoError(t, err)
+	fileBytes, err := ioutil.ReadAll(fileReader)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("CONFIG_BPF=y"), fileBytes)
+
+	restored.KernelConfiguration, restored.KernelSources = hydrated.KernelConfiguration, hydrated.KernelSources
+	assert.Equal(t, hydrated, restored)
+}
+
+func TestWrapWithoutDir(t *testing.T) {
+	fake := &fakeOperatingSystem{}
+
+	assert.Equal(t, fake, cache.Wrap(&cache.Opts{}, nil, fake))
+}
@@ -0,0 +1,33 @@
+package cos
+
+import "github.com/thought-machine/falco-probes/pkg/operatingsystem"
+
+// Architecture represents a CPU architecture which COS images are built for.
+type Architecture struct {
+	// Machine is the `uname -m` of the architecture's kernels.
+	Machine string
+	// ToolsBucket is the Google Cloud Storage bucket which the kernel headers and commits of the architecture's builds
+	// are published to.
+	ToolsBucket string
+	// KernelArch is the architecture's directory in the kernel sources, i.e. `arch/<KernelArch>/`.
+	KernelArch string
+	// Defconfigs are the names of the architecture's kernel configurations, in order of preference.
+	Defconfigs []string
+}
+
+var (
+	// X86_64 represents COS builds for x86_64.
+	X86_64 = &Architecture{
+		Machine:     operatingsystem.MachineX86_64,
+		ToolsBucket: "cos-tools",
+		KernelArch:  "x86",
+		Defconfigs:  []string{"lakitu", "x86_64"},
+	}
+	// Arm64 represents COS builds for arm64, e.g. on Google Cloud's Tau T2A machines.
+	Arm64 = &Architecture{
+		Machine:     operatingsystem.MachineAarch64,
+		ToolsBucket: "cos-tools-arm64",
+		KernelArch:  "arm64",
+		Defconfigs:  []string{"lakitu"},
+	}
+)
@@ -1,21 +1,25 @@
 package buildid
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
 )
 
 const (
 	maxConcurrent = 100
 	timeout       = 5 * time.Second
-	urlTemplate   = "https://storage.googleapis.com/cos-tools/%s/kernel_commit"
+	// DefaultURLTemplate is the default URLTemplate, the kernel commits in Google's COS tools buckets.
+	DefaultURLTemplate = "https://storage.googleapis.com/{bucket}/{build_id}/kernel_commit"
 )
 
 // ValidatorInterface is the interface we can override to mock validation.
 type ValidatorInterface interface {
-	FilterInvalid([]string) ([]string, error)
+	FilterInvalid(context.Context, string, []string) ([]string, error)
 }
 
 // Validator implements ValidatorInterface
@@ -23,6 +27,9 @@ type Validator struct {
 	ValidatorInterface
 
 	Client HTTPClient
+	// URLTemplate is the URL of a file which only exists for valid builds, where {bucket} and {build_id} are replaced
+	// by the COS tools bucket and the build id. Defaults to DefaultURLTemplate.
+	URLTemplate string
 }
 
 // HTTPClient is an interface we can use for a mock HTTP requests.
@@ -37,11 +44,16 @@ type ValidatorResult struct {
 	err     error
 }
 
-// FilterInvalid takes a list of build IDs and returns only ones which are valid releases.
-func (v Validator) FilterInvalid(buildIDsIn []string) ([]string, error) {
+// FilterInvalid takes a list of build IDs and returns only ones which are valid releases in the given COS tools bucket.
+func (v Validator) FilterInvalid(ctx context.Context, toolsBucket string, buildIDsIn []string) ([]string, error) {
 	if v.Client == nil {
 		v.Client = &http.Client{Timeout: timeout}
 	}
+	if v.URLTemplate == "" {
+		v.URLTemplate = DefaultURLTemplate
+	}
+	fetcher := fetch.New()
+	fetcher.Client = v.Client
 
 	buildIDsOut := make([]string, 0)
 
@@ -50,7 +62,7 @@ func (v Validator) FilterInvalid(buildIDsIn []string) ([]string, error) {
 	results := make(chan ValidatorResult)
 
 	for _, buildID := range buildIDsIn {
-		go v.validate(buildID, sem, results)
+		go v.validate(ctx, fetcher, toolsBucket, buildID, sem, results)
 	}
 	for range buildIDsIn {
 		result := <-results
@@ -64,35 +76,23 @@ func (v Validator) FilterInvalid(buildIDsIn []string) ([]string, error) {
 	return buildIDsOut, nil
 }
 
-func (v Validator) validate(buildID string, sem chan bool, results chan<- ValidatorResult) {
-    // If the buildID ends in .0.0 then filter it immediately as in all milestones there has never been
-    // a valid release matching this (and therefore it's a fairly good guess these are alpha versions).
-    // We can then ignore falco-driver-loader's COS_73_WORKAROUND choking on cos-101-17033-0-0 to
-    // cos-101-17109-0-0. See:
-    // https://cloud.google.com/container-optimized-os/docs/release-notes/m{101,97,93,89,85,81,77,73,69}
-    // https://github.com/draios/sysdig/pull/1431
-    if strings.HasSuffix(buildID, ".0.0") {
-        results <- ValidatorResult{buildID: buildID, valid: false, err: nil}
-        return
-    }
-	req, err := http.NewRequest(http.MethodHead, fmt.Sprintf(urlTemplate, buildID), nil)
-	if err != nil {
-		results <- ValidatorResult{buildID: buildID, valid: false, err: err}
+func (v Validator) validate(ctx context.Context, fetcher *fetch.Fetcher, toolsBucket string, buildID string, sem chan bool, results chan<- ValidatorResult) {
+	// If the buildID ends in .0.0 then filter it immediately as in all milestones there has never been
+	// a valid release matching this (and therefore it's a fairly good guess these are alpha versions).
+	// We can then ignore falco-driver-loader's COS_73_WORKAROUND choking on cos-101-17033-0-0 to
+	// cos-101-17109-0-0. See:
+	// https://cloud.google.com/container-optimized-os/docs/release-notes/m{101,97,93,89,85,81,77,73,69}
+	// https://github.com/draios/sysdig/pull/1431
+	if strings.HasSuffix(buildID, ".0.0") {
+		results <- ValidatorResult{buildID: buildID, valid: false, err: nil}
 		return
 	}
+	url := strings.NewReplacer("{bucket}", toolsBucket, "{build_id}", buildID).Replace(v.URLTemplate)
 	// Block until there is a free connection.
 	sem <- true
-	// Run the request.
-	res, err := v.Client.Do(req)
+	// Run the request, retrying it if it is rate limited or fails.
+	valid, err := fetcher.Exists(ctx, url)
 	// Release the buffer to allow the next connection.
 	<-sem
-	if err != nil {
-		results <- ValidatorResult{buildID: buildID, valid: false, err: err}
-		return
-	}
-	if res.StatusCode > 299 {
-		results <- ValidatorResult{buildID: buildID, valid: false}
-		return
-	}
-	results <- ValidatorResult{buildID: buildID, valid: true}
+	results <- ValidatorResult{buildID: buildID, valid: valid, err: err}
 }
@@ -1,7 +1,9 @@
 package buildid_test
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
@@ -23,6 +25,7 @@ func TestFilterInvalid(t *testing.T) {
 	validator.Client = &mock.HTTPClient{}
 	mock.GetDoFunc = func(req *http.Request) (*http.Response, error) {
 		re := regexp.MustCompile(`\d+\.\d+\.\d+`)
+		assert.Regexp(t, regexp.MustCompile(`^/cos-tools/`), req.URL.Path)
 		buildID := re.FindString(req.URL.Path)
 		// Return 200 if in expectedBuildIDs or 404 otherwise.
 		statusCode := 404
@@ -35,7 +38,22 @@ func TestFilterInvalid(t *testing.T) {
 		return &http.Response{StatusCode: statusCode}, nil
 	}
 
-	actualBuildIDs, err := validator.FilterInvalid(testBuildIDs)
+	actualBuildIDs, err := validator.FilterInvalid(context.Background(), "cos-tools", testBuildIDs)
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, expectedBuildIDs, actualBuildIDs)
 }
+
+func TestFilterInvalidURLTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mirror/cos-tools/17162.40.34/kernel_commit" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	validator := buildid.Validator{URLTemplate: server.URL + "/mirror/{bucket}/{build_id}/kernel_commit"}
+
+	actualBuildIDs, err := validator.FilterInvalid(context.Background(), "cos-tools", []string{"17162.40.35", "17162.40.34"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"17162.40.34"}, actualBuildIDs)
+}
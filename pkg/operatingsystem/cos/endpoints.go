@@ -0,0 +1,55 @@
+package cos
+
+import "strings"
+
+const (
+	// DefaultToolsURLTemplate is the default ToolsURLTemplate, Google's COS tools buckets.
+	DefaultToolsURLTemplate = "https://storage.googleapis.com/{bucket}/{build_id}/{file}"
+	// DefaultKernelConfigURLTemplate is the default KernelConfigURLTemplate, Google's COS kernel repository.
+	DefaultKernelConfigURLTemplate = "https://cos.googlesource.com/third_party/kernel/+/{kernel_commit}/arch/{arch}/configs/{defconfig}_defconfig?format=TEXT"
+	// DefaultKernelSourcesURLTemplate is the default KernelSourcesURLTemplate, Google's COS kernel repository.
+	DefaultKernelSourcesURLTemplate = "https://cos.googlesource.com/third_party/kernel/+archive/{kernel_commit}.tar.gz"
+	// DefaultVersionsURL is the default VersionsURL, Google's COS repository with all the COS milestone and build id
+	// information.
+	DefaultVersionsURL = "https://cos.googlesource.com/cos/manifest-snapshots"
+)
+
+// Endpoints represents where COS artifacts are fetched from, e.g. Google's or a mirror of them.
+type Endpoints struct {
+	// ToolsURLTemplate is the URL of the files published for COS builds (e.g. kernel-headers.tgz and kernel_commit),
+	// where {bucket}, {build_id} and {file} are replaced by the architecture's ToolsBucket, the build id and the file.
+	ToolsURLTemplate string
+	// KernelConfigURLTemplate is the URL of the base64 encoded kernel configurations in the COS kernel repository,
+	// where {kernel_commit}, {arch} and {defconfig} are replaced by the kernel commit, the architecture's KernelArch
+	// and one of its Defconfigs.
+	KernelConfigURLTemplate string
+	// KernelSourcesURLTemplate is the URL of tar archives of the COS kernel repository, which may be compressed with
+	// gzip, bzip2 or xz, where {kernel_commit} is replaced by the kernel commit.
+	KernelSourcesURLTemplate string
+	// VersionsURL is the URL of the git repository of COS manifest snapshots, whose branches are milestones and tags
+	// are build ids.
+	VersionsURL string
+}
+
+// toolsURL returns the URL of the given file published for the given build of the given architecture.
+func (e *Endpoints) toolsURL(architecture *Architecture, buildID string, file string) string {
+	return strings.NewReplacer(
+		"{bucket}", architecture.ToolsBucket,
+		"{build_id}", buildID,
+		"{file}", file,
+	).Replace(e.ToolsURLTemplate)
+}
+
+// kernelConfigURL returns the URL of the given kernel configuration of the given architecture at the given commit.
+func (e *Endpoints) kernelConfigURL(architecture *Architecture, kernelCommit string, defconfig string) string {
+	return strings.NewReplacer(
+		"{kernel_commit}", kernelCommit,
+		"{arch}", architecture.KernelArch,
+		"{defconfig}", defconfig,
+	).Replace(e.KernelConfigURLTemplate)
+}
+
+// kernelSourcesURL returns the URL of an archive of the kernel sources at the given commit.
+func (e *Endpoints) kernelSourcesURL(kernelCommit string) string {
+	return strings.ReplaceAll(e.KernelSourcesURLTemplate, "{kernel_commit}", kernelCommit)
+}
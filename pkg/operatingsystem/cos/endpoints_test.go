@@ -0,0 +1,103 @@
+package cos_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
+)
+
+// newCosServer returns a server serving the artifacts of the COS build 17162.40.34 like Google does, whose kernel
+// configuration is only found at the fallback defconfig.
+func newCosServer(t *testing.T) *httptest.Server {
+	headers := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(headers)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "./usr/src/linux-headers-5.15.65+/", Mode: 0o755, Typeflag: tar.TypeDir}))
+	compileH := []byte("#define UTS_MACHINE \"x86_64\"\n#define UTS_VERSION \"#1 SMP Thu Nov 10 10:13:28 UTC 2022\"\n")
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "./usr/src/linux-headers-5.15.65+/include/generated/compile.h", Mode: 0o644, Size: int64(len(compileH)), Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write(compileH)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	responses := map[string][]byte{
+		"/cos-tools/17162.40.34/kernel_commit":                                  []byte("c19d150c6bd658510ec786390aec80ad476c7578\n"),
+		"/cos-tools/17162.40.34/kernel-headers.tgz":                             headers.Bytes(),
+		"/kernel/c19d150c6bd658510ec786390aec80ad476c7578/x86/x86_64_defconfig": []byte(base64.StdEncoding.EncodeToString([]byte("CONFIG_BPF=y\n"))),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// configureEndpoints configures cos to fetch from the given server and versions repository until the test finishes.
+func configureEndpoints(t *testing.T, server *httptest.Server, versionsURL string) {
+	cos.Configure(&cos.Opts{
+		ManifestSnapshotsDir:    filepath.Join(t.TempDir(), "manifest-snapshots"),
+		MinMilestone:            cos.MilestoneMin,
+		ToolsURLTemplate:        server.URL + "/{bucket}/{build_id}/{file}",
+		KernelConfigURLTemplate: server.URL + "/kernel/{kernel_commit}/{arch}/{defconfig}_defconfig",
+		VersionsURL:             versionsURL,
+	})
+	t.Cleanup(func() {
+		cos.Configure(&cos.Opts{MinMilestone: cos.MilestoneMin})
+	})
+}
+
+func TestGetKernelPackageNamesFromEndpoints(t *testing.T) {
+	versionsDir := t.TempDir()
+	versions := initRepository(t, versionsDir)
+	commitBuildID(t, versions, "101", "17162.40.34")
+	// not published to the tools bucket, so is not valid.
+	commitBuildID(t, versions, "101", "17162.40.42")
+
+	configureEndpoints(t, newCosServer(t), versionsDir)
+	validator := cos.BuildIDValidator
+	cos.BuildIDValidator = nil
+	defer func() { cos.BuildIDValidator = validator }()
+
+	res, err := cos.NewCos(nil).GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cos-101-17162-40-34"}, res.Names())
+}
+
+func TestGetKernelPackageByNameFromEndpoints(t *testing.T) {
+	configureEndpoints(t, newCosServer(t), "")
+	cli := docker.MustClient()
+
+	res, err := cos.NewCos(cli).GetKernelPackageByName(context.Background(), "cos-101-17162-40-34")
+	require.NoError(t, err)
+	defer cli.MustRemoveVolumes(res.KernelConfiguration, res.KernelSources)
+
+	assert.Equal(t, "5.15.65+", res.KernelRelease)
+	assert.Equal(t, "#1 SMP Thu Nov 10 10:13:28 UTC 2022", res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+
+	fileReader, err := cli.GetFileFromVolume(res.KernelConfiguration, "/lib/modules/", "/lib/modules/5.15.65+/config")
+	require.NoError(t, err)
+	fileBytes, err := ioutil.ReadAll(fileReader)
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIG_BPF=y\n", string(fileBytes))
+}
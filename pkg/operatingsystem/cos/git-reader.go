@@ -10,10 +10,6 @@ import (
 )
 
 const (
-	// MilestoneMin is the lowest active milestone. Lower value milestones are not built. See
-	// https://cloud.google.com/container-optimized-os/docs/concepts/versioning.
-	MilestoneMin = 93
-
 	milestonePrefix = "origin/release-R"
 	buildIDFormat   = "%d.%d.%d"
 )
@@ -73,9 +69,10 @@ func readMilestonesToRefs(repository *git.Repository, url string) (map[int]*plum
 	return milestonesToRefs, nil
 }
 
-// ReadMilestonesToBuildIDs returns a map of milestone with a list of build IDs for that milestone.
+// ReadMilestonesToBuildIDs returns a map of the milestones selected by the given policy with a list of build IDs for
+// that milestone.
 // See https://cos.googlesource.com/cos/manifest-snapshots/+log/refs/heads/release-R101/
-func ReadMilestonesToBuildIDs(repository *git.Repository, url string) (map[int][]string, error) {
+func ReadMilestonesToBuildIDs(repository *git.Repository, url string, policy *MilestonePolicy) (map[int][]string, error) {
 	milestonesToBuildIDs := make(map[int][]string)
 
 	milestonesToRefs, err := readMilestonesToRefs(repository, url)
@@ -88,11 +85,13 @@ func ReadMilestonesToBuildIDs(repository *git.Repository, url string) (map[int][
 		return nil, fmt.Errorf("could not list build ids for %s: %w", url, err)
 	}
 
-	for milestone, ref := range milestonesToRefs {
-		if milestone < MilestoneMin {
-			continue
-		}
+	milestones := make([]int, 0, len(milestonesToRefs))
+	for milestone := range milestonesToRefs {
+		milestones = append(milestones, milestone)
+	}
 
+	for _, milestone := range policy.Select(milestones) {
+		ref := milestonesToRefs[milestone]
 		log, err := repository.Log(&git.LogOptions{
 			From: ref.Hash(),
 		})
@@ -22,7 +22,7 @@ func TestReadMilestonesToBuildIDs(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	milestonesToBuildIDs, err := cos.ReadMilestonesToBuildIDs(repository, url)
+	milestonesToBuildIDs, err := cos.ReadMilestonesToBuildIDs(repository, url, &cos.MilestonePolicy{Min: cos.MilestoneMin})
 	assert.NoError(t, err)
 
 	assert.Contains(t, milestonesToBuildIDs, expectedMilestone)
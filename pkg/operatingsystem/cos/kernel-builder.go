@@ -0,0 +1,36 @@
+package cos
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// KernelBuilderDockerfile represents the contents of a Dockerfile to build the kernelbuilder image which prepares COS
+// kernel sources with their kernel configuration. COS kernels are built with LLVM, so the toolchain published for each
+// build is used when there is one, and ubuntu's LLVM installed alongside the usual kernel build dependencies otherwise.
+// The kernel sources are only prepared when the clang used is the version the kernel was built with.
+const KernelBuilderDockerfile = `FROM ubuntu:24.04
+RUN apt-get update \
+	&& apt-get install -y bc bison build-essential clang flex libelf-dev libssl-dev lld llvm \
+	&& rm -rf /var/lib/apt/lists/*
+`
+
+// KernelBuilderRepository is the repository to build the kernelbuilder image under.
+const KernelBuilderRepository = "docker.io/thoughtmachine/falco-cos-kernelbuilder"
+
+// BuildKernelBuilder builds the kernelbuilder docker image for the given machine (`uname -m`), which prepares the
+// kernel sources of that machine.
+func BuildKernelBuilder(dockerClient *docker.Client, kernelMachine string) (string, error) {
+	imageFQN := fmt.Sprintf("%s:%s", KernelBuilderRepository, docker.TagForMachine("latest", kernelMachine))
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: KernelBuilderDockerfile,
+		Tags:       []string{imageFQN},
+		Platform:   docker.PlatformForMachine(kernelMachine),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -4,17 +4,17 @@ import (
 	"archive/tar"
 	"bufio"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/thought-machine/falco-probes/internal/logging"
 	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/fetch"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 )
 
@@ -25,22 +25,14 @@ const (
 
 	// kernelReleasePattern expects a kernel release like 5.15.73+.
 	kernelReleasePattern = `^([0-9]+\.){2}[0-9]+\+$`
-	// rateLimitTries is number of tries (including the original) when https://cos.googlesource.com rate limits us.
-	rateLimitTries = 3
-	// rateLimitSecondsBase is number of seconds multiplied by the current try (1, ..., rateLimitTries - 1) that the code will wait to call the COS repo again.
-	// This cannot be too long otherwise the Github action may timeout.
-	rateLimitSecondsBase       = 5
-	urlCosKernelConfigTemplate = "https://cos.googlesource.com/third_party/kernel/+/%s/arch/x86/configs/%s_defconfig?format=TEXT"
-	urlCosToolsTemplate        = "https://storage.googleapis.com/cos-tools/%s/%s"
 )
 
-var (
-	arches = [...]string{"lakitu", "x86_64"}
-	log    = logging.Logger
-)
+var log = logging.Logger
 
-// NewKernelPackage returns a new hydrated example implementation operatingsystem.KernelPackage.
-func NewKernelPackage(dockerClient *docker.Client, name string) (*operatingsystem.KernelPackage, error) {
+// NewKernelPackage returns a new hydrated example implementation operatingsystem.KernelPackage for the given
+// architecture's build of the given image name, fetched from the given endpoints with the given fetcher. Its kernel
+// sources are only fetched and prepared if withKernelSources is given.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, name string, withKernelSources bool) (*operatingsystem.KernelPackage, error) {
 	kP := &operatingsystem.KernelPackage{
 		OperatingSystem: "cos",
 		Name:            name,
@@ -51,11 +43,11 @@ func NewKernelPackage(dockerClient *docker.Client, name string) (*operatingsyste
 		return nil, err
 	}
 
-	if err := addKernelReleaseAndVersionAndMachine(dockerClient, kP, version); err != nil {
+	if err := addKernelReleaseAndVersionAndMachine(ctx, dockerClient, kP, fetcher, endpoints, architecture, version); err != nil {
 		return nil, err
 	}
 
-	if err := addSourcesAndConfiguration(dockerClient, kP, version); err != nil {
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, fetcher, endpoints, architecture, version, withKernelSources); err != nil {
 		return nil, err
 	}
 
@@ -66,15 +58,15 @@ func NewKernelPackage(dockerClient *docker.Client, name string) (*operatingsyste
 	return kP, nil
 }
 
-// Falco doesn't require the Google COS sources so don't bother to fetch them and just create an empty volume for the
-// interface.
-func addSourcesAndConfiguration(dockerClient *docker.Client, kp *operatingsystem.KernelPackage, version *Version) error {
-	kernelCommit, err := readKernelCommit(version.BuildID)
+// Falco's eBPF probe doesn't require the Google COS sources so, unless withKernelSources is given, don't bother to
+// fetch them and just create an empty volume for the interface.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, version *Version, withKernelSources bool) error {
+	kernelCommit, err := readKernelCommit(ctx, fetcher, endpoints, architecture, version.BuildID)
 	if err != nil {
 		return err
 	}
 
-	encodedKernelConfig, err := readKernelConfig(version.BuildID, kernelCommit)
+	encodedKernelConfig, err := readKernelConfig(ctx, fetcher, endpoints, architecture, version.BuildID, kernelCommit)
 	if err != nil {
 		return err
 	}
@@ -84,11 +76,15 @@ func addSourcesAndConfiguration(dockerClient *docker.Client, kp *operatingsystem
 		return err
 	}
 
-	commandTemplate := "mkdir -p /usr/src/kernels && mkdir -p '/lib/modules/%s'"
+	commandTemplate := "mkdir -p /usr/src/kernels && mkdir -p '/lib/modules/%[1]s'"
+	if withKernelSources {
+		commandTemplate += " && mkdir -p '/usr/src/kernels/%[1]s'"
+	}
 
 	kp.KernelConfiguration = dockerClient.MustCreateVolume()
 	kp.KernelSources = dockerClient.MustCreateVolume()
 	_, err = dockerClient.Run(
+		ctx,
 		&docker.RunOpts{
 			Image:      BusyBoxImage,
 			Entrypoint: []string{"/bin/sh"},
@@ -108,9 +104,120 @@ func addSourcesAndConfiguration(dockerClient *docker.Client, kp *operatingsystem
 		return err
 	}
 
+	if withKernelSources {
+		return addKernelSources(ctx, dockerClient, kp, fetcher, endpoints, architecture, version.BuildID, kernelCommit)
+	}
+
+	return nil
+}
+
+// addKernelSources fetches the kernel sources at the given kernel commit into the sources volume at
+// `/usr/src/kernels/<kernel release>`, and prepares them with the kernel configuration in the configuration volume, as
+// well as the conventional `/lib/modules/<kernel release>/build` symlink to them. They are prepared with the toolchain
+// published for the build when there is one, and with the kernelbuilder's otherwise, failing if its clang is not the
+// version the kernel was built with.
+func addKernelSources(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, buildID string, kernelCommit string) error {
+	kernelBuilderImage, err := BuildKernelBuilder(dockerClient, architecture.Machine)
+	if err != nil {
+		return fmt.Errorf("could not build kernelbuilder: %w", err)
+	}
+
+	kernelSources, err := fetcher.Get(ctx, endpoints.kernelSourcesURL(kernelCommit))
+	if err != nil {
+		return fmt.Errorf("could not get kernel sources for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
+	}
+	defer kernelSources.Close()
+
+	err = dockerClient.WriteTarToVolume(ctx, kp.KernelSources, "/usr/src/", fmt.Sprintf("/usr/src/kernels/%s/", kp.KernelRelease), kernelSources)
+	if err != nil {
+		return fmt.Errorf("could not extract kernel sources for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
+	}
+
+	toolchain, err := readToolchain(ctx, dockerClient, fetcher, endpoints, architecture, buildID)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.MustRemoveVolumes(toolchain)
+
+	// The kernel sources are an archive rather than a git checkout, so they lack the local version (e.g. `+`) of COS
+	// kernel releases, which is added back as a localversion file for modules to be built for the same release.
+	script := `
+set -euo pipefail
+release="%[1]s"
+src="/usr/src/kernels/${release}"
+make_args=()
+if [ -x /toolchain/bin/clang ]; then
+	export PATH="/toolchain/bin:${PATH}"
+fi
+if grep -q '^CONFIG_CC_IS_CLANG=y' "/lib/modules/${release}/config"; then
+	make_args+=(LLVM=1)
+	config_clang_version="$(sed -n 's/^CONFIG_CLANG_VERSION=//p' "/lib/modules/${release}/config")"
+	clang_version="$(echo __clang_major__ __clang_minor__ __clang_patchlevel__ | clang -E -P - | awk 'NF { print $1 * 10000 + $2 * 100 + $3 }')"
+	if [ "${clang_version}" != "${config_clang_version}" ]; then
+		echo "clang ${clang_version} is not the clang ${config_clang_version} the kernel was built with" >&2
+		exit 1
+	fi
+fi
+cp "/lib/modules/${release}/config" "${src}/.config"
+make -s -C "${src}" "${make_args[@]}" olddefconfig
+sources_release="$(make -s -C "${src}" "${make_args[@]}" kernelrelease)"
+case "${release}" in
+"${sources_release}"*)
+	printf '%%s' "${release#"${sources_release}"}" > "${src}/localversion-cos"
+	;;
+*)
+	echo "kernel sources are for ${sources_release} rather than ${release}" >&2
+	exit 1
+	;;
+esac
+make -s -C "${src}" "${make_args[@]}" modules_prepare
+ln -sfn "${src}" "/lib/modules/${release}/build"
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      kernelBuilderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.KernelRelease)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+				toolchain:              "/toolchain/",
+			},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not prepare kernel sources for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
+	}
+
 	return nil
 }
 
+// readToolchain returns a volume with the toolchain published for the given build extracted into it, which is empty if
+// no toolchain is published for the build (e.g. for older builds).
+func readToolchain(ctx context.Context, dockerClient *docker.Client, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, buildID string) (operatingsystem.Volume, error) {
+	toolchainVol := dockerClient.MustCreateVolume()
+
+	toolchain, err := fetcher.Get(ctx, endpoints.toolsURL(architecture, buildID, "toolchain.tar.xz"))
+	if fetch.IsNotFound(err) {
+		log.Warn().Str("build_id", buildID).Msg("no toolchain is published for build id, preparing kernel sources with the kernelbuilder's")
+		return toolchainVol, nil
+	}
+	if err != nil {
+		dockerClient.MustRemoveVolumes(toolchainVol)
+		return "", fmt.Errorf("could not get toolchain for build id %s: %w", buildID, err)
+	}
+	defer toolchain.Close()
+
+	if err := dockerClient.WriteTarToVolume(ctx, toolchainVol, "/toolchain/", "/toolchain/", toolchain); err != nil {
+		dockerClient.MustRemoveVolumes(toolchainVol)
+		return "", fmt.Errorf("could not extract toolchain for build id %s: %w", buildID, err)
+	}
+
+	return toolchainVol, nil
+}
+
 func addOSRelease(dockerClient *docker.Client, kp *operatingsystem.KernelPackage, version *Version) error {
 	osReleaseTemplate := `
 ID=cos
@@ -144,8 +251,8 @@ BUILD_ID=%s
 	return nil
 }
 
-func addKernelReleaseAndVersionAndMachine(dockerClient *docker.Client, kp *operatingsystem.KernelPackage, version *Version) error {
-	kernelHeaders, err := readKernelHeaders(version.BuildID)
+func addKernelReleaseAndVersionAndMachine(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, version *Version) error {
+	kernelHeaders, err := readKernelHeaders(ctx, fetcher, endpoints, architecture, version.BuildID)
 	if err != nil {
 		return err
 	}
@@ -153,30 +260,13 @@ func addKernelReleaseAndVersionAndMachine(dockerClient *docker.Client, kp *opera
 	return extractKernelDetails(version.BuildID, kernelHeaders, kp)
 }
 
-func readKernelHeaders(buildID string) (io.ReadCloser, error) {
-	for readTry := 1; readTry <= rateLimitTries; readTry++ {
-		resp, err := http.Get(fmt.Sprintf(urlCosToolsTemplate, buildID, "kernel-headers.tgz"))
-		if err != nil {
-			return nil, fmt.Errorf("could not get kernel headers for build id %s: %w", buildID, err)
-		}
-
-		// The Google COS repo can rate limit us with a 429 so do some basic backoff and retry logic.
-		if resp.StatusCode == 429 {
-			if readTry == rateLimitTries {
-				return nil, fmt.Errorf("rate limited %d times with 429 for kernel headers for build id %s: %s", rateLimitTries, buildID, resp.Status)
-			}
-			time.Sleep(time.Duration(rateLimitSecondsBase * readTry) * time.Second)
-			continue
-		}
-
-		if resp.StatusCode > 299 {
-			return nil, fmt.Errorf("could not get 2XX response for kernel headers for build id %s: %s", buildID, resp.Status)
-		}
-
-		return resp.Body, nil
+func readKernelHeaders(ctx context.Context, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, buildID string) (io.ReadCloser, error) {
+	kernelHeaders, err := fetcher.Get(ctx, endpoints.toolsURL(architecture, buildID, "kernel-headers.tgz"))
+	if err != nil {
+		return nil, fmt.Errorf("could not get kernel headers for build id %s: %w", buildID, err)
 	}
 
-	return nil, nil
+	return kernelHeaders, nil
 }
 
 func extractKernelDetails(buildID string, kernelHeaders io.Reader, kp *operatingsystem.KernelPackage) error {
@@ -240,7 +330,7 @@ func extractKernelDetails(buildID string, kernelHeaders io.Reader, kp *operating
 			}
 			err := scanner.Err()
 			if err != nil {
-				return fmt.Errorf("could not read lines of generate in kernel headers archive for build id %s: %s in %s", buildID, header.Typeflag, header.Name)
+				return fmt.Errorf("could not read lines of %s in kernel headers archive for build id %s: %w", header.Name, buildID, err)
 			}
 		}
 
@@ -252,83 +342,49 @@ func extractKernelDetails(buildID string, kernelHeaders io.Reader, kp *operating
 	return nil
 }
 
-func readKernelCommit(buildID string) (string, error) {
-	for readTry := 1; readTry <= rateLimitTries; readTry++ {
-		resp, err := http.Get(fmt.Sprintf(urlCosToolsTemplate, buildID, "kernel_commit"))
+func readKernelCommit(ctx context.Context, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, buildID string) (string, error) {
+	body, err := fetcher.Get(ctx, endpoints.toolsURL(architecture, buildID, "kernel_commit"))
+	if err != nil {
+		return "", fmt.Errorf("could not get kernel commit for build id %s: %w", buildID, err)
+	}
+	defer body.Close()
 
-		if err != nil {
-			return "", fmt.Errorf("could not get kernel commit for build id %s: %w", buildID, err)
-		}
-		defer resp.Body.Close()
+	kernelCommit, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("could not read kernel commit for build id %s: %w", buildID, err)
+	}
 
-		// The Google COS repo can rate limit us with a 429 so do some basic backoff and retry logic.
-		if resp.StatusCode == 429 {
-			if readTry == rateLimitTries {
-				return "", fmt.Errorf("rate limited %d times with 429 for kernel commit for build id %s: %s", rateLimitTries, buildID, resp.Status)
-			}
-			time.Sleep(time.Duration(rateLimitSecondsBase * readTry) * time.Second)
+	return strings.TrimSuffix(string(kernelCommit), "\n"), nil
+}
+
+func readKernelConfig(ctx context.Context, fetcher *fetch.Fetcher, endpoints *Endpoints, architecture *Architecture, buildID string, kernelCommit string) (string, error) {
+	defconfigLastIndex := len(architecture.Defconfigs) - 1
+	for i, defconfig := range architecture.Defconfigs {
+		body, err := fetcher.Get(ctx, endpoints.kernelConfigURL(architecture, kernelCommit, defconfig))
+
+		// If the preferred config is not found, retry with the next one.
+		if fetch.IsNotFound(err) && i < defconfigLastIndex {
+			log.Warn().
+				Str("build_id", buildID).
+				Str("kernel_commit", kernelCommit).
+				Str("defconfig", defconfig).
+				Msg("could not find config for")
 			continue
 		}
-
-		if resp.StatusCode > 299 {
-			return "", fmt.Errorf("could not get 2XX response for kernel commit for build id %s: %s", buildID, resp.Status)
+		if err != nil {
+			return "", fmt.Errorf("could not get kernel config for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
 		}
+		defer body.Close()
 
-		body, err := io.ReadAll(resp.Body)
+		kernelConfig, err := io.ReadAll(body)
 		if err != nil {
-			return "", fmt.Errorf("could not read kernel commit for build id %s: %w", buildID, err)
+			return "", fmt.Errorf("could not read kernel config for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
 		}
 
-		return strings.TrimSuffix(string(body), "\n"), nil
-	}
-
-	return "", nil
-}
-
-func readKernelConfig(buildID string, kernelCommit string) (string, error) {
-	body := make([]byte, 0)
-
-	archLastIndex := len(arches) - 1
-	for i, arch := range arches {
-		for readTry := 1; readTry <= rateLimitTries; readTry++ {
-			resp, err := http.Get(fmt.Sprintf(urlCosKernelConfigTemplate, kernelCommit, arch))
-			if err != nil {
-				return "", fmt.Errorf("could not get kernel config for build id %s (kernel commit %s): %w", buildID, kernelCommit, err)
-			}
-
-			// The Google COS repo can rate limit us with a 429 so do some basic backoff and retry logic.
-			if resp.StatusCode == 429 {
-				if readTry == rateLimitTries {
-					return "", fmt.Errorf("rate limited %d times with 429 for kernel config for build id %s (kernel commit %s): %s", rateLimitTries, buildID, kernelCommit, resp.Status)
-				}
-				time.Sleep(time.Duration(rateLimitSecondsBase * readTry) * time.Second)
-				continue
-			}
-
-			// If the config for the preferred architecture is not found, retry with the next one.
-			if resp.StatusCode == 404 && i < archLastIndex {
-				log.Warn().
-					Str("build_id", buildID).
-					Str("kernel_commit", kernelCommit).
-					Str("architecture", arch).
-					Msg("could not find config for")
-				continue
-			}
-
-			if resp.StatusCode > 299 {
-				return "", fmt.Errorf("could not get 2XX response for kernel config for build id %s (kernel commit %s): %s", buildID, kernelCommit, resp.Status)
-			}
-
-			body, err = io.ReadAll(resp.Body)
-			if err != nil {
-				return "", fmt.Errorf("could not read kernel commit for build id %s: %w", buildID, err)
-			}
-
-			return string(body), nil
-		}
+		return string(kernelConfig), nil
 	}
 
-	return "", nil
+	return "", fmt.Errorf("no defconfigs to get kernel config for build id %s (kernel commit %s)", buildID, kernelCommit)
 }
 
 func decodeKernelConfig(buildID string, kernelCommit string, encodedKernelConfig string) (string, error) {
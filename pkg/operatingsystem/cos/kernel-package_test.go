@@ -1,11 +1,13 @@
 package cos_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/fetch"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
 )
@@ -17,10 +19,11 @@ func TestGetKernelConfiguration(t *testing.T) {
 	cli := docker.MustClient()
 
 	// TODO: mock the http calls.
-	kp, err := cos.NewKernelPackage(cli, "cos-89-16108-403-11")
+	kp, err := cos.NewKernelPackage(context.Background(), cli, fetch.New(), (&cos.Opts{}).Endpoints(), cos.X86_64, "cos-89-16108-403-11", false)
 	require.NoError(t, err)
 
 	out, err := cli.Run(
+		context.Background(),
 		&docker.RunOpts{
 			Image:      "docker.io/library/busybox:latest",
 			Entrypoint: []string{"/bin/sh"},
@@ -41,10 +44,11 @@ func TestGetKernelSources(t *testing.T) {
 	cli := docker.MustClient()
 
 	// TODO: mock the http calls.
-	kp, err := cos.NewKernelPackage(cli, "cos-101-17162-40-34")
+	kp, err := cos.NewKernelPackage(context.Background(), cli, fetch.New(), (&cos.Opts{}).Endpoints(), cos.X86_64, "cos-101-17162-40-34", false)
 	require.NoError(t, err)
 
 	out, err := cli.Run(
+		context.Background(),
 		&docker.RunOpts{
 			Image:      "docker.io/library/busybox:latest",
 			Entrypoint: []string{"ls"},
@@ -57,3 +61,28 @@ func TestGetKernelSources(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, out)
 }
+
+// Tests that the kernel sources are fetched and prepared for building modules against when they are asked for.
+func TestGetKernelSourcesWithKernelSources(t *testing.T) {
+	cli := docker.MustClient()
+
+	// TODO: mock the http calls.
+	kp, err := cos.NewKernelPackage(context.Background(), cli, fetch.New(), (&cos.Opts{}).Endpoints(), cos.X86_64, "cos-101-17162-40-34", true)
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", "cat /lib/modules/*/build/include/config/kernel.release && ls /lib/modules/*/build/scripts/mod/"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, kp.KernelRelease)
+	assert.Contains(t, out, "modpost")
+}
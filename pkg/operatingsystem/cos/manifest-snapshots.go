@@ -0,0 +1,106 @@
+package cos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// manifestSnapshotsDir returns the directory to keep the clone of the manifest-snapshots repository in, or "" if
+// there is nowhere to keep it.
+func manifestSnapshotsDir() string {
+	if options.ManifestSnapshotsDir != "" {
+		return options.ManifestSnapshotsDir
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine the user's cache directory, cloning manifest-snapshots into memory")
+		return ""
+	}
+
+	return filepath.Join(cacheDir, "falco-probes", "cos", "manifest-snapshots")
+}
+
+// FetchRepository returns the git repository at the given URL, kept as a bare clone in the given directory. The
+// repository is cloned on first use and only fetched incrementally afterwards, unless the clone in the directory is of
+// another URL in which case it is replaced by a clone of the given URL. If no directory is given, the repository is
+// cloned into memory instead.
+func FetchRepository(ctx context.Context, dir string, url string) (*git.Repository, error) {
+	if dir == "" {
+		repository, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+			URL: url,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not clone %s: %w", url, err)
+		}
+
+		return repository, nil
+	}
+
+	repository, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return cloneRepository(ctx, dir, url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", dir, err)
+	}
+
+	remote, err := repository.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the remote of %s: %w", dir, err)
+	}
+	if remoteURLs := remote.Config().URLs; len(remoteURLs) == 0 || remoteURLs[0] != url {
+		log.Info().Strs("remote_urls", remoteURLs).Str("url", url).Str("dir", dir).Msg("replacing clone of another repository")
+		return cloneRepository(ctx, dir, url)
+	}
+
+	err = repository.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		Tags:       git.AllTags,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("could not fetch %s into %s: %w", url, dir, err)
+	}
+
+	return repository, nil
+}
+
+// cloneRepository clones the git repository at the given URL into the given directory as a bare repository, replacing
+// anything already in the directory. It is cloned into a temporary directory first so that an interrupted clone is not
+// mistaken for a complete one.
+func cloneRepository(ctx context.Context, dir string, url string) (*git.Repository, error) {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := ioutil.TempDir(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := git.PlainCloneContext(ctx, tmpDir, true, &git.CloneOptions{
+		URL:  url,
+		Tags: git.AllTags,
+	}); err != nil {
+		return nil, fmt.Errorf("could not clone %s: %w", url, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return nil, err
+	}
+
+	return git.PlainOpen(dir)
+}
@@ -0,0 +1,99 @@
+package cos_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
+)
+
+var testSignature = &object.Signature{Name: "COS", Email: "cos@example.com", When: time.Date(2022, time.November, 10, 0, 0, 0, 0, time.UTC)}
+
+// initRepository returns a new repository with an initial commit which milestones' release branches are created from.
+func initRepository(t *testing.T, dir string) *git.Repository {
+	repository, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	worktree, err := repository.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Commit("Initial commit", &git.CommitOptions{Author: testSignature})
+	require.NoError(t, err)
+
+	return repository
+}
+
+// commitBuildID commits to the given milestone's release branch of the given repository and tags the commit with the
+// given build id, like the manifest-snapshots repository.
+func commitBuildID(t *testing.T, repository *git.Repository, milestone string, buildID string) {
+	worktree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	branch := plumbing.NewBranchReferenceName("release-R" + milestone)
+	if _, err := repository.Reference(branch, false); err != nil {
+		master, err := repository.Reference(plumbing.Master, false)
+		require.NoError(t, err)
+		require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Hash: master.Hash(), Branch: branch, Create: true}))
+	} else {
+		require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: branch}))
+	}
+
+	hash, err := worktree.Commit(buildID, &git.CommitOptions{Author: testSignature})
+	require.NoError(t, err)
+
+	_, err = repository.CreateTag(buildID, hash, &git.CreateTagOptions{Tagger: testSignature, Message: buildID})
+	require.NoError(t, err)
+}
+
+func TestFetchRepository(t *testing.T) {
+	upstreamDir := t.TempDir()
+	upstream := initRepository(t, upstreamDir)
+	commitBuildID(t, upstream, "81", "13310.1416.0")
+	commitBuildID(t, upstream, "101", "17162.40.34")
+
+	mirrorDir := filepath.Join(t.TempDir(), "manifest-snapshots")
+
+	mirror, err := cos.FetchRepository(context.Background(), mirrorDir, upstreamDir)
+	require.NoError(t, err)
+	milestonesToBuildIDs, err := cos.ReadMilestonesToBuildIDs(mirror, upstreamDir, &cos.MilestonePolicy{Min: cos.MilestoneMin})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]string{101: {"17162.40.34"}}, milestonesToBuildIDs)
+
+	commitBuildID(t, upstream, "101", "17162.40.42")
+
+	mirror, err = cos.FetchRepository(context.Background(), mirrorDir, upstreamDir)
+	require.NoError(t, err)
+	milestonesToBuildIDs, err = cos.ReadMilestonesToBuildIDs(mirror, upstreamDir, &cos.MilestonePolicy{Min: cos.MilestoneMin})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]string{101: {"17162.40.42", "17162.40.34"}}, milestonesToBuildIDs)
+}
+
+func TestFetchRepositoryOfAnotherURL(t *testing.T) {
+	upstreamDir := t.TempDir()
+	upstream := initRepository(t, upstreamDir)
+	commitBuildID(t, upstream, "101", "17162.40.34")
+
+	otherUpstreamDir := t.TempDir()
+	otherUpstream := initRepository(t, otherUpstreamDir)
+	commitBuildID(t, otherUpstream, "105", "17412.1.2")
+
+	mirrorDir := filepath.Join(t.TempDir(), "manifest-snapshots")
+
+	_, err := cos.FetchRepository(context.Background(), mirrorDir, upstreamDir)
+	require.NoError(t, err)
+
+	mirror, err := cos.FetchRepository(context.Background(), mirrorDir, otherUpstreamDir)
+	require.NoError(t, err)
+	remote, err := mirror.Remote(git.DefaultRemoteName)
+	require.NoError(t, err)
+	assert.Equal(t, []string{otherUpstreamDir}, remote.Config().URLs)
+	milestonesToBuildIDs, err := cos.ReadMilestonesToBuildIDs(mirror, otherUpstreamDir, &cos.MilestonePolicy{Min: cos.MilestoneMin})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]string{105: {"17412.1.2"}}, milestonesToBuildIDs)
+}
@@ -0,0 +1,60 @@
+package cos
+
+import "sort"
+
+const (
+	// MilestoneMin is the lowest milestone to list kernel packages of by default. Lower value milestones are not
+	// built. See https://cloud.google.com/container-optimized-os/docs/concepts/versioning.
+	MilestoneMin = 93
+
+	// firstLTSMilestone is the first Long-Term Support milestone, since which every fourth milestone is one.
+	firstLTSMilestone   = 69
+	ltsMilestoneCadence = 4
+)
+
+// IsLTSMilestone returns whether the given milestone is a Long-Term Support milestone. See
+// https://cloud.google.com/container-optimized-os/docs/concepts/versioning.
+func IsLTSMilestone(milestone int) bool {
+	return milestone >= firstLTSMilestone && (milestone-firstLTSMilestone)%ltsMilestoneCadence == 0
+}
+
+// MilestonePolicy represents which milestones to list kernel packages of.
+type MilestonePolicy struct {
+	// Milestones are the only milestones to select if any are given, in which case Min is ignored.
+	Milestones []int
+	// Min is the lowest milestone to select.
+	Min int
+	// OnlyLTS only selects Long-Term Support milestones.
+	OnlyLTS bool
+	// Newest only selects this many of the newest milestones which are otherwise selected, if greater than 0.
+	Newest int
+}
+
+// Select returns the given milestones which are selected by this policy, newest first.
+func (p *MilestonePolicy) Select(milestones []int) []int {
+	explicit := map[int]bool{}
+	for _, milestone := range p.Milestones {
+		explicit[milestone] = true
+	}
+
+	selected := []int{}
+	for _, milestone := range milestones {
+		if len(explicit) > 0 && !explicit[milestone] {
+			continue
+		}
+		if len(explicit) == 0 && milestone < p.Min {
+			continue
+		}
+		if p.OnlyLTS && !IsLTSMilestone(milestone) {
+			continue
+		}
+		selected = append(selected, milestone)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(selected)))
+	if p.Newest > 0 && len(selected) > p.Newest {
+		selected = selected[:p.Newest]
+	}
+
+	return selected
+}
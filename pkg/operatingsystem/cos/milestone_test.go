@@ -0,0 +1,40 @@
+package cos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
+)
+
+func TestIsLTSMilestone(t *testing.T) {
+	for _, milestone := range []int{69, 93, 97, 101, 105, 109, 113} {
+		assert.True(t, cos.IsLTSMilestone(milestone), milestone)
+	}
+	for _, milestone := range []int{65, 94, 102, 103, 104} {
+		assert.False(t, cos.IsLTSMilestone(milestone), milestone)
+	}
+}
+
+func TestMilestonePolicySelect(t *testing.T) {
+	milestones := []int{89, 93, 97, 101, 102, 105, 106}
+
+	var tests = []struct {
+		name     string
+		policy   *cos.MilestonePolicy
+		expected []int
+	}{
+		{"min", &cos.MilestonePolicy{Min: cos.MilestoneMin}, []int{106, 105, 102, 101, 97, 93}},
+		{"explicit", &cos.MilestonePolicy{Min: cos.MilestoneMin, Milestones: []int{89, 101, 113}}, []int{101, 89}},
+		{"only lts", &cos.MilestonePolicy{Min: cos.MilestoneMin, OnlyLTS: true}, []int{105, 101, 97, 93}},
+		{"newest", &cos.MilestonePolicy{Min: cos.MilestoneMin, Newest: 2}, []int{106, 105}},
+		{"newest lts", &cos.MilestonePolicy{OnlyLTS: true, Newest: 2}, []int{105, 101}},
+		{"newest more than available", &cos.MilestonePolicy{Milestones: []int{101}, Newest: 2}, []int{101}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.Select(milestones))
+		})
+	}
+}
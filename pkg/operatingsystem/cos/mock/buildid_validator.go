@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos/buildid"
 )
 
@@ -10,6 +12,6 @@ type BuildIDValidator struct {
 }
 
 // FilterInvalid just returns all build IDs.
-func (v BuildIDValidator) FilterInvalid(buildIDsIn []string) ([]string, error) {
+func (v BuildIDValidator) FilterInvalid(ctx context.Context, toolsBucket string, buildIDsIn []string) ([]string, error) {
 	return buildIDsIn, nil
 }
@@ -1,12 +1,12 @@
 package cos
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/fetch"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos/buildid"
 )
@@ -14,19 +14,87 @@ import (
 const (
 	// Name represents the name of this operating system.
 	Name = "cos"
-
-	// URL versions is the Google COS repo with all the COS milestone and build id information.
-	urlVersions = "https://cos.googlesource.com/cos/manifest-snapshots"
+	// Arm64Name represents the name of this operating system for arm64 builds.
+	Arm64Name = Name + "-arm64"
 )
 
 // BuildIDValidator does what it says on the tin.
 var BuildIDValidator buildid.ValidatorInterface
 
+// Opts represents the options for cos.
+type Opts struct {
+	ManifestSnapshotsDir string `long:"manifest_snapshots_dir" description:"The directory to keep a clone of the COS manifest-snapshots repository in, which is fetched incrementally across runs (default: falco-probes/cos/manifest-snapshots in the user's cache directory)" env:"COS_MANIFEST_SNAPSHOTS_DIR"`
+	Milestones           []int  `long:"milestone" description:"A milestone to list kernel packages of, may be given multiple times, ignoring --min_milestone (default: all milestones)" env:"COS_MILESTONES" env-delim:","`
+	MinMilestone         int    `long:"min_milestone" description:"The lowest milestone to list kernel packages of" default:"93" env:"COS_MIN_MILESTONE"`
+	OnlyLTSMilestones    bool   `long:"only_lts_milestones" description:"Only list kernel packages of Long-Term Support milestones" env:"COS_ONLY_LTS_MILESTONES"`
+	NewestMilestones     int    `long:"newest_milestones" description:"Only list kernel packages of this many of the newest milestones which are otherwise listed (default: all milestones)" env:"COS_NEWEST_MILESTONES"`
+
+	ToolsURLTemplate         string `long:"tools_url_template" description:"The URL of the files published for COS builds, where {bucket}, {build_id} and {file} are replaced (default: Google's COS tools buckets)" env:"COS_TOOLS_URL_TEMPLATE"`
+	KernelConfigURLTemplate  string `long:"kernel_config_url_template" description:"The URL of the base64 encoded COS kernel configurations, where {kernel_commit}, {arch} and {defconfig} are replaced (default: Google's COS kernel repository)" env:"COS_KERNEL_CONFIG_URL_TEMPLATE"`
+	KernelSourcesURLTemplate string `long:"kernel_sources_url_template" description:"The URL of archives of the COS kernel sources, where {kernel_commit} is replaced (default: Google's COS kernel repository)" env:"COS_KERNEL_SOURCES_URL_TEMPLATE"`
+	VersionsURL              string `long:"versions_url" description:"The URL of the git repository of COS manifest snapshots (default: Google's cos/manifest-snapshots)" env:"COS_VERSIONS_URL"`
+
+	KernelSources bool   `long:"kernel_sources" description:"Fetch the full kernel sources of COS builds and prepare them with their kernel configuration, e.g. to build kernel module drivers, rather than leaving them empty" env:"COS_KERNEL_SOURCES"`
+	HTTPCacheDir  string `long:"http_cache_dir" description:"The directory to cache fetched COS artifacts in, which are revalidated with their ETag rather than fetched again (default: not cached)" env:"COS_HTTP_CACHE_DIR"`
+}
+
+var options = &Opts{
+	MinMilestone: MilestoneMin,
+}
+
+// MilestonePolicy returns the MilestonePolicy given by these options.
+func (o *Opts) MilestonePolicy() *MilestonePolicy {
+	return &MilestonePolicy{
+		Milestones: o.Milestones,
+		Min:        o.MinMilestone,
+		OnlyLTS:    o.OnlyLTSMilestones,
+		Newest:     o.NewestMilestones,
+	}
+}
+
+// Endpoints returns the Endpoints given by these options, defaulting to Google's.
+func (o *Opts) Endpoints() *Endpoints {
+	endpoints := &Endpoints{
+		ToolsURLTemplate:         o.ToolsURLTemplate,
+		KernelConfigURLTemplate:  o.KernelConfigURLTemplate,
+		KernelSourcesURLTemplate: o.KernelSourcesURLTemplate,
+		VersionsURL:              o.VersionsURL,
+	}
+	if endpoints.ToolsURLTemplate == "" {
+		endpoints.ToolsURLTemplate = DefaultToolsURLTemplate
+	}
+	if endpoints.KernelConfigURLTemplate == "" {
+		endpoints.KernelConfigURLTemplate = DefaultKernelConfigURLTemplate
+	}
+	if endpoints.KernelSourcesURLTemplate == "" {
+		endpoints.KernelSourcesURLTemplate = DefaultKernelSourcesURLTemplate
+	}
+	if endpoints.VersionsURL == "" {
+		endpoints.VersionsURL = DefaultVersionsURL
+	}
+
+	return endpoints
+}
+
+// Fetcher returns the fetch.Fetcher given by these options.
+func (o *Opts) Fetcher() *fetch.Fetcher {
+	fetcher := fetch.New()
+	fetcher.CacheDir = o.HTTPCacheDir
+
+	return fetcher
+}
+
+// Configure configures the cos operating systems with the given options.
+func Configure(opts *Opts) {
+	options = opts
+}
+
 // Cos implements operatingsystem.OperatingSystem for cos.
 type Cos struct {
 	operatingsystem.OperatingSystem
 
 	dockerClient *docker.Client
+	architecture *Architecture
 }
 
 // Version represents the milestone and build id of a Google COS version which can be found
@@ -40,49 +108,91 @@ type Version struct {
 func NewCos(dockerClient *docker.Client) operatingsystem.OperatingSystem {
 	return &Cos{
 		dockerClient: dockerClient,
+		architecture: X86_64,
+	}
+}
+
+// NewCosArm64 returns a new cos implementation of operatingsystem.OperatingSystem for arm64 builds.
+func NewCosArm64(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Cos{
+		dockerClient: dockerClient,
+		architecture: Arm64,
 	}
 }
 
 // GetName implements operatingsystem.OperatingSystem.GetName for cos.
 func (s *Cos) GetName() string {
+	if s.architecture == Arm64 {
+		return Arm64Name
+	}
+
 	return Name
 }
 
 // GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for cos.
 // Note that KernelPackageNames in this context are Google COS Image Names.
-func (s *Cos) GetKernelPackageNames() ([]string, error) {
-	packageNames := make([]string, 0)
+func (s *Cos) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	refs := operatingsystem.KernelPackageRefs{}
 
-	repository, _ := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
-		URL: urlVersions,
-	})
+	endpoints := options.Endpoints()
 
-	milestonesToBuildIDs, err := ReadMilestonesToBuildIDs(repository, urlVersions)
+	repository, err := FetchRepository(ctx, manifestSnapshotsDir(), endpoints.VersionsURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch milestones and build ids: %w", err)
+	}
+
+	milestonesToBuildIDs, err := ReadMilestonesToBuildIDs(repository, endpoints.VersionsURL, options.MilestonePolicy())
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve milestones and build ids: %w", err)
 	}
 
-	if BuildIDValidator == nil {
-		BuildIDValidator = buildid.Validator{}
+	validator := BuildIDValidator
+	if validator == nil {
+		validator = buildid.Validator{
+			URLTemplate: strings.ReplaceAll(endpoints.ToolsURLTemplate, "{file}", "kernel_commit"),
+		}
 	}
 
 	for milestone, candidateBuildIDs := range milestonesToBuildIDs {
-		validBuildIDs, err := BuildIDValidator.FilterInvalid(candidateBuildIDs)
+		validBuildIDs, err := validator.FilterInvalid(ctx, s.architecture.ToolsBucket, candidateBuildIDs)
 		if err != nil {
 			return nil, fmt.Errorf("could not filter invalid build ids: %w", err)
 		}
 
+		support := operatingsystem.SupportActive
+		if IsLTSMilestone(milestone) {
+			support = operatingsystem.SupportLTS
+		}
+
 		for _, buildID := range validBuildIDs {
-			packageNames = append(packageNames, fmt.Sprintf("cos-%d-%s", milestone, strings.ReplaceAll(buildID, ".", "-")))
+			refs = append(refs, operatingsystem.KernelPackageRef{
+				Name:         fmt.Sprintf("cos-%d-%s", milestone, strings.ReplaceAll(buildID, ".", "-")),
+				Architecture: s.architecture.Machine,
+				Repository:   fmt.Sprintf("release-R%d", milestone),
+				Support:      support,
+			})
 		}
 	}
 
-	return packageNames, nil
+	return refs, nil
 }
 
 // GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for cos.
-func (s *Cos) GetKernelPackageByName(name string) (*operatingsystem.KernelPackage, error) {
-	return NewKernelPackage(s.dockerClient, name)
+func (s *Cos) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, options.Fetcher(), options.Endpoints(), s.architecture, name, options.KernelSources)
+}
+
+// CacheKey implements operatingsystem.CacheKeyer for cos, as its kernel packages differ with whether their kernel
+// sources are fetched and the endpoints they are fetched from.
+func (s *Cos) CacheKey() string {
+	endpoints := options.Endpoints()
+
+	return strings.Join([]string{
+		fmt.Sprintf("kernel_sources=%t", options.KernelSources),
+		endpoints.ToolsURLTemplate,
+		endpoints.KernelConfigURLTemplate,
+		endpoints.KernelSourcesURLTemplate,
+	}, "\x00")
 }
 
 // ParseVersion takes an image name (e.g. "cos-101-17162-40-34") and returns the milestone (eg. 101) and build ID (e.g.
@@ -1,10 +1,13 @@
 package cos_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos/mock"
 )
@@ -16,7 +19,7 @@ func TestGetKernelPackageNames(t *testing.T) {
 	// Mock the validator to avoid making calls to Google's COS release bucket when testing.
 	cos.BuildIDValidator = mock.BuildIDValidator{}
 
-	res, err := os.GetKernelPackageNames()
+	res, err := os.GetKernelPackageNames(context.Background())
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, res)
@@ -26,7 +29,7 @@ func TestGetKernelPackageByName(t *testing.T) {
 	cli := docker.MustClient()
 	os := cos.NewCos(cli)
 
-	res, err := os.GetKernelPackageByName("cos-101-17162-40-34")
+	res, err := os.GetKernelPackageByName(context.Background(), "cos-101-17162-40-34")
 	assert.NoError(t, err)
 
 	assert.Equal(t, "5.15.65+", res.KernelRelease)
@@ -45,3 +48,36 @@ func TestParseVersion(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedVersion, actualVersion)
 }
+
+func TestGetKernelPackageByNameArm64(t *testing.T) {
+	cli := docker.MustClient()
+	os := cos.NewCosArm64(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "cos-105-17412-101-24")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "aarch64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "NAME=\"Container-Optimized OS\"")
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
+
+func TestCacheKey(t *testing.T) {
+	t.Cleanup(func() {
+		cos.Configure(&cos.Opts{MinMilestone: cos.MilestoneMin})
+	})
+	os, ok := cos.NewCos(nil).(operatingsystem.CacheKeyer)
+	require.True(t, ok)
+
+	cos.Configure(&cos.Opts{MinMilestone: cos.MilestoneMin})
+	defaultKey := os.CacheKey()
+	cos.Configure(&cos.Opts{MinMilestone: 101})
+	assert.Equal(t, defaultKey, os.CacheKey(), "listing options do not change kernel packages")
+
+	cos.Configure(&cos.Opts{MinMilestone: cos.MilestoneMin, KernelSources: true})
+	assert.NotEqual(t, defaultKey, os.CacheKey())
+
+	cos.Configure(&cos.Opts{MinMilestone: cos.MilestoneMin, ToolsURLTemplate: "http://localhost/{bucket}/{build_id}/{file}"})
+	assert.NotEqual(t, defaultKey, os.CacheKey())
+}
@@ -0,0 +1,31 @@
+package debian
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// AptDownloaderDockerfile represents the contents of a Dockerfile to build the aptdownloader image which lists and
+// downloads packages from Debian's current stable release and its backports suite.
+const AptDownloaderDockerfile = `FROM debian:stable-slim
+RUN . /etc/os-release \
+	&& echo "deb http://deb.debian.org/debian ${VERSION_CODENAME}-backports main" > /etc/apt/sources.list.d/backports.list
+`
+
+// AptDownloaderRepository is the repository to build the aptdownloader image under.
+const AptDownloaderRepository = "docker.io/thoughtmachine/falco-aptdownloader"
+
+// BuildAptDownloader builds the aptdownloader docker image.
+func BuildAptDownloader(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:debian", AptDownloaderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: AptDownloaderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
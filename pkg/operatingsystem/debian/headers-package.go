@@ -0,0 +1,63 @@
+package debian
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// headersPackageRe matches a line of `<package> <version>` for the architecture specific kernel headers packages of
+// Debian's default amd64 kernels, e.g.
+// `linux-headers-6.1.0-9-amd64 6.1.27-1` -> 6.1.0-9, 6.1.27-1 or
+// `linux-headers-6.4.0-0.deb12.2-amd64 6.4.4-3~bpo12+1` -> 6.4.0-0.deb12.2, 6.4.4-3~bpo12+1 or
+// `linux-headers-6.12.41+deb13-amd64 6.12.41-1` -> 6.12.41+deb13, 6.12.41-1.
+// The cloud and realtime flavours (e.g. `linux-headers-6.1.0-9-cloud-amd64`) are not matched.
+var headersPackageRe = regexp.MustCompile(`^linux-headers-([0-9]+\.[0-9]+\.[0-9]+[-+][0-9a-z.+~]+)-amd64 (\S+)$`)
+
+// HeadersPackage represents the Debian packages which provide the kernel headers for a Debian kernel release.
+type HeadersPackage struct {
+	// ABI is the kernel ABI that the headers are for, e.g. `6.1.0-9`.
+	ABI string
+	// KernelRelease is the kernel release that the headers are for, e.g. `6.1.0-9-amd64`.
+	KernelRelease string
+	// Version is the Debian package version of the headers, e.g. `6.1.27-1`.
+	Version string
+}
+
+// ParseHeadersPackages returns the kernel headers packages listed in the given `<package> <version>` lines, keyed by
+// kernel release.
+func ParseHeadersPackages(out string) map[string]*HeadersPackage {
+	headersPackages := map[string]*HeadersPackage{}
+	for _, line := range strings.Split(out, "\n") {
+		matches := headersPackageRe.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		abi, version := matches[1], matches[2]
+		kernelRelease := abi + "-amd64"
+		headersPackages[kernelRelease] = &HeadersPackage{
+			ABI:           abi,
+			KernelRelease: kernelRelease,
+			Version:       version,
+		}
+	}
+
+	return headersPackages
+}
+
+// PackageNames returns the names of the Debian packages which provide the kernel headers: the architecture specific
+// and architecture independent headers packages.
+func (hp *HeadersPackage) PackageNames() []string {
+	return []string{
+		fmt.Sprintf("linux-headers-%s-amd64=%s", hp.ABI, hp.Version),
+		fmt.Sprintf("linux-headers-%s-common=%s", hp.ABI, hp.Version),
+	}
+}
+
+// KernelVersion returns the value of `uname -v` for the kernel built from this HeadersPackage. Debian kernels are only
+// built once per package version so their build number is always 1, and falco-driver-loader reads nothing but it and
+// the package version (which it names probes after) from it, so the build timestamp is omitted.
+func (hp *HeadersPackage) KernelVersion() string {
+	return fmt.Sprintf("#1 SMP Debian %s", hp.Version)
+}
@@ -0,0 +1,42 @@
+package debian_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/debian"
+)
+
+const testHeadersPackagesOut = `linux-headers-6.1.0-9-amd64 6.1.27-1
+linux-headers-6.1.0-9-cloud-amd64 6.1.27-1
+linux-headers-6.1.0-9-rt-amd64 6.1.27-1
+linux-headers-6.4.0-0.deb12.2-amd64 6.4.4-3~bpo12+1
+linux-headers-amd64 6.1.27-1
+`
+
+func TestParseHeadersPackages(t *testing.T) {
+	headersPackages := debian.ParseHeadersPackages(testHeadersPackagesOut)
+
+	assert.Equal(t, map[string]*debian.HeadersPackage{
+		"6.1.0-9-amd64": {
+			ABI:           "6.1.0-9",
+			KernelRelease: "6.1.0-9-amd64",
+			Version:       "6.1.27-1",
+		},
+		"6.4.0-0.deb12.2-amd64": {
+			ABI:           "6.4.0-0.deb12.2",
+			KernelRelease: "6.4.0-0.deb12.2-amd64",
+			Version:       "6.4.4-3~bpo12+1",
+		},
+	}, headersPackages)
+}
+
+func TestHeadersPackage(t *testing.T) {
+	headersPackage := &debian.HeadersPackage{ABI: "6.12.41+deb13", KernelRelease: "6.12.41+deb13-amd64", Version: "6.12.41-1"}
+
+	assert.Equal(t, []string{
+		"linux-headers-6.12.41+deb13-amd64=6.12.41-1",
+		"linux-headers-6.12.41+deb13-common=6.12.41-1",
+	}, headersPackage.PackageNames())
+	assert.Equal(t, "#1 SMP Debian 6.12.41-1", headersPackage.KernelVersion())
+}
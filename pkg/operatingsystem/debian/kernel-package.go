@@ -0,0 +1,93 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const kernelMachine = "x86_64"
+
+var debianImage = "docker.io/library/debian:stable-slim"
+
+// NewKernelPackage returns a new hydrated Debian implementation of operatingsystem.KernelPackage for the given
+// HeadersPackage.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, headersPackage *HeadersPackage) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            headersPackage.KernelRelease,
+		KernelRelease:   headersPackage.KernelRelease,
+		KernelVersion:   headersPackage.KernelVersion(),
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, headersPackage); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, debianImage)
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the headers packages, and the linux-kbuild package they depend on, into the
+// sources volume and links them to where falco-driver-builder expects to find them (`/usr/src/kernels/<kernel release>`),
+// as well as the conventional `/lib/modules/<kernel release>/build` symlink and configuration in the configuration
+// volume. The headers packages refer to linux-kbuild in `/usr/lib` and to each other by absolute paths, so these are
+// rewritten to be relative to the sources volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, headersPackage *HeadersPackage) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	aptDownloaderImage, err := BuildAptDownloader(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build aptdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+apt-get update -qq
+cd "$(mktemp -d)"
+kbuild="$(apt-cache depends linux-headers-%[2]s | grep -o 'linux-kbuild-\S*' | head -n1)"
+apt-get download -qq %[1]s "${kbuild}"
+for deb in *.deb; do dpkg -x "${deb}" ./root; done
+cp -a ./root/usr/src/. /usr/src/
+cp -a ./root/usr/lib/linux-kbuild-* /usr/src/
+for link in $(find /usr/src/linux-headers-* -maxdepth 1 -type l); do
+	target="$(readlink "${link}")"
+	case "${target}" in
+	*lib/linux-kbuild-*) ln -sfn "../${target#*lib/}" "${link}" ;;
+	esac
+done
+sed -i 's#/usr/src/#$(abspath $(dir $(realpath $(lastword $(MAKEFILE_LIST))))/..)/#g' /usr/src/linux-headers-%[2]s/Makefile
+mkdir -p /usr/src/kernels /lib/modules/%[2]s
+ln -sfn ../linux-headers-%[2]s /usr/src/kernels/%[2]s
+ln -sfn /usr/src/kernels/%[2]s /lib/modules/%[2]s/build
+cp /usr/src/linux-headers-%[2]s/.config /lib/modules/%[2]s/config
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      aptDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, strings.Join(headersPackage.PackageNames(), " "), kp.KernelRelease)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package debian_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/debian"
+)
+
+func TestGetKernelSourcesAndConfiguration(t *testing.T) {
+	cli := docker.MustClient()
+	os := debian.NewDebian(cli)
+
+	// Debian's archive only keeps the latest kernels so we cannot hardcode a kernel package name.
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	kp, err := os.GetKernelPackageByName(context.Background(), names[0].Name)
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/" + kp.KernelRelease + "/Makefile && " +
+				"ls /usr/src/kernels/" + kp.KernelRelease + "/scripts/Makefile.build && " +
+				"ls /lib/modules/" + kp.KernelRelease + "/build && " +
+				"grep CONFIG_BPF= /lib/modules/" + kp.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
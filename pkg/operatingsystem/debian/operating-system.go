@@ -0,0 +1,107 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system
+const Name = "debian"
+
+// Debian implements operatingsystem.OperatingSystem for Debian's current stable release and its backports suite.
+type Debian struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+
+	// headersPackages caches the listing of the Debian archive as the kernel release alone is not enough to know the
+	// version of its headers packages.
+	headersPackages     map[string]*HeadersPackage
+	headersPackagesLock sync.Mutex
+}
+
+// NewDebian returns a new debian implementation of operatingsystem.OperatingSystem.
+func NewDebian(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Debian{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for debian.
+func (s *Debian) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for debian.
+// Note that KernelPackageNames in this context are kernel releases, e.g. `6.1.0-9-amd64`.
+func (s *Debian) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	headersPackages, err := s.getHeadersPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packageNames := []string{}
+	for kernelRelease := range headersPackages {
+		if operatingsystem.IsEBPFCompatible(kernelRelease) {
+			packageNames = append(packageNames, kernelRelease)
+		}
+	}
+	sort.Strings(packageNames)
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.KernelRelease = ref.Name
+		ref.Architecture = kernelMachine
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for debian.
+func (s *Debian) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	headersPackages, err := s.getHeadersPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headersPackage, ok := headersPackages[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find kernel headers for %s", name)
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, headersPackage)
+}
+
+func (s *Debian) getHeadersPackages(ctx context.Context) (map[string]*HeadersPackage, error) {
+	s.headersPackagesLock.Lock()
+	defer s.headersPackagesLock.Unlock()
+
+	if s.headersPackages != nil {
+		return s.headersPackages, nil
+	}
+
+	aptDownloaderImage, err := BuildAptDownloader(s.dockerClient)
+	if err != nil {
+		return nil, fmt.Errorf("could not build aptdownloader: %w", err)
+	}
+
+	out, err := s.dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      aptDownloaderImage,
+			Entrypoint: []string{"bash"},
+			Cmd: []string{"-c", "apt-get update -qq && " +
+				"apt-cache --names-only search '^linux-headers-.*-amd64$' | cut -d' ' -f1 | " +
+				"xargs apt-cache show | awk '/^Package:/ { p = $2 } /^Version:/ { print p, $2 }'"},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not list kernel headers packages: %w", err)
+	}
+
+	s.headersPackages = ParseHeadersPackages(out)
+
+	return s.headersPackages, nil
+}
@@ -0,0 +1,49 @@
+package debian_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/debian"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := debian.NewDebian(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+[-+][0-9a-z.+~]+-amd64$`), name)
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := debian.NewDebian(cli)
+
+	// Debian's archive only keeps the latest kernels so we cannot hardcode a kernel package name.
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	name := names[len(names)-1].Name
+
+	res, err := os.GetKernelPackageByName(context.Background(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "debian", res.OperatingSystem)
+	assert.Equal(t, name, res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP Debian `), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=debian")
+	assert.Regexp(t, regexp.MustCompile(`^falco_debian_\d+\.\d+\.\d+-\d+-(rt-|cloud-)?amd64_1$`), res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
@@ -0,0 +1,150 @@
+package declarative
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultKernelPackagePattern = `^\S+$`
+	defaultKernelMachine        = "x86_64"
+)
+
+// Definition declares an operating system whose kernel packages are listed and hydrated by shell snippets run in a
+// container image, e.g. an internal distribution's image with its package manager configured.
+//
+//	operating_systems:
+//	  - name: internal-linux
+//	    target_id: internal
+//	    image: registry.example.com/internal-linux:2
+//	    list_kernel_packages: |
+//	      dnf --quiet repoquery --queryformat '%{version}-%{release}.%{arch}' kernel-devel
+//	    populate_kernel_package: |
+//	      cd "$(mktemp -d)"
+//	      dnf --quiet download "kernel-devel-${KERNEL_PACKAGE}"
+//	      rpm2cpio ./*.rpm | cpio --extract --make-directories
+//	      cp -a ./usr/src/kernels/. /usr/src/kernels/
+//	      mkdir -p "/lib/modules/${KERNEL_PACKAGE}"
+//	      ln -sfn "/usr/src/kernels/${KERNEL_PACKAGE}" "/lib/modules/${KERNEL_PACKAGE}/build"
+//	      cp "/usr/src/kernels/${KERNEL_PACKAGE}/.config" "/lib/modules/${KERNEL_PACKAGE}/config"
+type Definition struct {
+	// Name is the name of the operating system, e.g. `internal-linux`.
+	Name string `yaml:"name"`
+	// TargetID is the target ID falco-driver-loader resolves on the operating system's hosts, i.e. the `ID` in its
+	// `/etc/os-release`, defaulting to Name.
+	TargetID string `yaml:"target_id"`
+	// Image is the container image to run the snippets in.
+	Image string `yaml:"image"`
+	// ListKernelPackages is the shell snippet which outputs the operating system's kernel package names.
+	ListKernelPackages string `yaml:"list_kernel_packages"`
+	// KernelPackagePattern is the regular expression which lines output by ListKernelPackages must match to be
+	// kernel package names, defaulting to any line without whitespace. This ignores any other output, e.g. from a
+	// package manager refreshing its metadata.
+	KernelPackagePattern string `yaml:"kernel_package_pattern"`
+	// PopulateKernelPackage is the shell snippet which populates the kernel sources and configuration of the kernel
+	// package named by `$KERNEL_PACKAGE`. It must populate the kernel build tree at
+	// `/usr/src/kernels/<kernel release>`, where falco-driver-builder expects to find it, and should populate the
+	// conventional `/lib/modules/<kernel release>/build` symlink and `/lib/modules/<kernel release>/config`.
+	PopulateKernelPackage string `yaml:"populate_kernel_package"`
+	// OSRelease is where to source the operating system's `/etc/os-release` from, defaulting to Image.
+	OSRelease struct {
+		// Image is a container image to copy `/etc/os-release` from.
+		Image string `yaml:"image"`
+		// Contents are the literal contents of `/etc/os-release`.
+		Contents string `yaml:"contents"`
+	} `yaml:"os_release"`
+	// KernelVersion is the `uname -v` of the operating system's kernels, defaulting to the UTS_VERSION in the kernel
+	// build tree's generated headers.
+	KernelVersion string `yaml:"kernel_version"`
+	// KernelMachine is the `uname -m` of the operating system's kernels, defaulting to `x86_64`.
+	KernelMachine string `yaml:"kernel_machine"`
+
+	kernelPackageRe *regexp.Regexp
+}
+
+type definitions struct {
+	OperatingSystems []*Definition `yaml:"operating_systems"`
+}
+
+// LoadDefinitions reads and validates the operating system definitions in the YAML file at the given path.
+func LoadDefinitions(path string) ([]*Definition, error) {
+	definitionsBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read operating system definitions: %w", err)
+	}
+
+	defs, err := ParseDefinitions(definitionsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse operating system definitions %s: %w", path, err)
+	}
+
+	return defs, nil
+}
+
+// ParseDefinitions parses and validates the operating system definitions in the given YAML document, filling in
+// their defaults.
+func ParseDefinitions(definitionsYAML []byte) ([]*Definition, error) {
+	defs := &definitions{}
+	if err := yaml.Unmarshal(definitionsYAML, defs); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for i, def := range defs.OperatingSystems {
+		if err := def.init(); err != nil {
+			return nil, fmt.Errorf("invalid operating system %d (%s): %w", i, def.Name, err)
+		}
+
+		if names[def.Name] {
+			return nil, fmt.Errorf("duplicate operating system %s", def.Name)
+		}
+		names[def.Name] = true
+	}
+
+	return defs.OperatingSystems, nil
+}
+
+// init validates the Definition and fills in its defaults.
+func (d *Definition) init() error {
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"name", d.Name},
+		{"image", d.Image},
+		{"list_kernel_packages", d.ListKernelPackages},
+		{"populate_kernel_package", d.PopulateKernelPackage},
+	} {
+		if field.value == "" {
+			return fmt.Errorf("missing %s", field.name)
+		}
+	}
+
+	if d.OSRelease.Image != "" && d.OSRelease.Contents != "" {
+		return fmt.Errorf("only one of os_release.image and os_release.contents may be given")
+	}
+
+	if d.TargetID == "" {
+		d.TargetID = d.Name
+	}
+	if d.KernelPackagePattern == "" {
+		d.KernelPackagePattern = defaultKernelPackagePattern
+	}
+	if d.OSRelease.Image == "" && d.OSRelease.Contents == "" {
+		d.OSRelease.Image = d.Image
+	}
+	if d.KernelMachine == "" {
+		d.KernelMachine = defaultKernelMachine
+	}
+
+	kernelPackageRe, err := regexp.Compile(d.KernelPackagePattern)
+	if err != nil {
+		return fmt.Errorf("invalid kernel_package_pattern: %w", err)
+	}
+	d.kernelPackageRe = kernelPackageRe
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package declarative_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/declarative"
+)
+
+const testDefinitionsYAML = `
+operating_systems:
+  - name: internal-linux
+    image: registry.example.com/internal-linux:2
+    list_kernel_packages: dnf --quiet repoquery kernel-devel
+    populate_kernel_package: dnf --quiet download "kernel-devel-${KERNEL_PACKAGE}"
+  - name: internal-linux-rt
+    target_id: internal
+    image: registry.example.com/internal-linux:2
+    list_kernel_packages: dnf --quiet repoquery kernel-rt-devel
+    kernel_package_pattern: '\.rt\d+$'
+    populate_kernel_package: dnf --quiet download "kernel-rt-devel-${KERNEL_PACKAGE}"
+    os_release:
+      contents: |
+        ID=internal
+    kernel_version: '#1 SMP PREEMPT_RT'
+    kernel_machine: aarch64
+`
+
+func TestParseDefinitions(t *testing.T) {
+	defs, err := declarative.ParseDefinitions([]byte(testDefinitionsYAML))
+	require.NoError(t, err)
+	require.Len(t, defs, 2)
+
+	assert.Equal(t, "internal-linux", defs[0].Name)
+	assert.Equal(t, "internal-linux", defs[0].TargetID)
+	assert.Equal(t, `^\S+$`, defs[0].KernelPackagePattern)
+	assert.Equal(t, "registry.example.com/internal-linux:2", defs[0].OSRelease.Image)
+	assert.Equal(t, "", defs[0].KernelVersion)
+	assert.Equal(t, "x86_64", defs[0].KernelMachine)
+
+	assert.Equal(t, "internal-linux-rt", defs[1].Name)
+	assert.Equal(t, "internal", defs[1].TargetID)
+	assert.Equal(t, "", defs[1].OSRelease.Image)
+	assert.Equal(t, "ID=internal\n", defs[1].OSRelease.Contents)
+	assert.Equal(t, "#1 SMP PREEMPT_RT", defs[1].KernelVersion)
+	assert.Equal(t, "aarch64", defs[1].KernelMachine)
+}
+
+func TestParseDefinitionsInvalid(t *testing.T) {
+	var tests = []struct {
+		desc string
+		yaml string
+	}{
+		{"missing image", "operating_systems: [{name: a, list_kernel_packages: ls, populate_kernel_package: ls}]"},
+		{"missing snippet", "operating_systems: [{name: a, image: b, list_kernel_packages: ls}]"},
+		{"invalid pattern", "operating_systems: [{name: a, image: b, list_kernel_packages: ls, populate_kernel_package: ls, kernel_package_pattern: '('}]"},
+		{"both os-release sources", "operating_systems: [{name: a, image: b, list_kernel_packages: ls, populate_kernel_package: ls, os_release: {image: c, contents: d}}]"},
+		{"duplicate", "operating_systems: [{name: a, image: b, list_kernel_packages: ls, populate_kernel_package: ls}, {name: a, image: c, list_kernel_packages: ls, populate_kernel_package: ls}]"},
+		{"not yaml", "operating_systems: ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := declarative.ParseDefinitions([]byte(tt.yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseKernelPackageNames(t *testing.T) {
+	defs, err := declarative.ParseDefinitions([]byte(testDefinitionsYAML))
+	require.NoError(t, err)
+
+	out := `Updating metadata for internal-linux
+5.14.0-1.el9.x86_64
+5.14.0-1.el9.x86_64
+5.14.0-2.rt1
+`
+
+	assert.Equal(t, []string{"5.14.0-1.el9.x86_64", "5.14.0-2.rt1"}, defs[0].ParseKernelPackageNames(out))
+	assert.Equal(t, []string{"5.14.0-2.rt1"}, defs[1].ParseKernelPackageNames(out))
+}
@@ -0,0 +1,88 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// NewKernelPackage returns a new hydrated declarative implementation of operatingsystem.KernelPackage for the given
+// Definition.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, definition *Definition, name string) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: definition.TargetID,
+		Name:            name,
+		KernelVersion:   definition.KernelVersion,
+		KernelMachine:   definition.KernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, definition); err != nil {
+		return nil, err
+	}
+
+	if err := addOSRelease(ctx, dockerClient, kP, definition); err != nil {
+		return nil, err
+	}
+
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kP.KernelSources)
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelRelease = kernelRelease
+
+	if kP.KernelVersion == "" {
+		kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kP.KernelSources, kP.KernelRelease, "UTS_VERSION")
+		if err != nil {
+			return nil, fmt.Errorf("%w, declare kernel_version instead", err)
+		}
+		kP.KernelVersion = kernelVersion
+	}
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration runs the definition's PopulateKernelPackage snippet with the sources and configuration
+// volumes mounted at `/usr/src/` and `/lib/modules/`.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, definition *Definition) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	_, err := dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      definition.Image,
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", "set -e\n" + definition.PopulateKernelPackage},
+			Env: map[string]string{
+				"KERNEL_PACKAGE": kp.Name,
+			},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addOSRelease(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, definition *Definition) error {
+	if definition.OSRelease.Contents != "" {
+		kp.OSRelease = operatingsystem.FileContents(definition.OSRelease.Contents)
+
+		return nil
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, definition.OSRelease.Image)
+	if err != nil {
+		return err
+	}
+	kp.OSRelease = osRelease
+
+	return nil
+}
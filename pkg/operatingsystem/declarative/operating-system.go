@@ -0,0 +1,74 @@
+package declarative
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Declarative implements operatingsystem.OperatingSystem for an operating system declared by a Definition.
+type Declarative struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	definition   *Definition
+}
+
+// NewOperatingSystem returns a new declarative implementation of operatingsystem.OperatingSystem for this
+// Definition.
+func (d *Definition) NewOperatingSystem(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Declarative{
+		dockerClient: dockerClient,
+		definition:   d,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for a declared operating system.
+func (s *Declarative) GetName() string {
+	return s.definition.Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for a declared operating
+// system by running its ListKernelPackages snippet.
+func (s *Declarative) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	out, err := s.dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      s.definition.Image,
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", "set -e\n" + s.definition.ListKernelPackages},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return operatingsystem.NewKernelPackageRefs(s.definition.ParseKernelPackageNames(out), func(ref *operatingsystem.KernelPackageRef) {
+		ref.Architecture = s.definition.KernelMachine
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for a declared operating
+// system.
+func (s *Declarative) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, s.definition, name)
+}
+
+// ParseKernelPackageNames returns the kernel package names from the given output of the ListKernelPackages snippet,
+// ignoring any lines which do not match the KernelPackagePattern.
+func (d *Definition) ParseKernelPackageNames(out string) []string {
+	packageNames := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || seen[name] || !d.kernelPackageRe.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+		packageNames = append(packageNames, name)
+	}
+
+	return packageNames
+}
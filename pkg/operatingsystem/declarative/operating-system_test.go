@@ -0,0 +1,81 @@
+package declarative_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/declarative"
+)
+
+// testBusyBoxDefinitionYAML declares an operating system which fabricates its kernel packages so that the declarative
+// implementation can be tested without a real distribution.
+const testBusyBoxDefinitionYAML = `
+operating_systems:
+  - name: busybox-linux
+    target_id: busybox
+    image: docker.io/library/busybox:1.33.1
+    list_kernel_packages: |
+      echo "fetching kernels..."
+      echo 5.10.0-1
+      echo 5.10.0-2
+    populate_kernel_package: |
+      release="${KERNEL_PACKAGE}-busybox"
+      mkdir -p "/usr/src/kernels/${release}/include/generated" "/lib/modules/${release}"
+      echo '#define UTS_VERSION "#3 SMP Mon Jan 2 00:00:00 UTC 2023"' > "/usr/src/kernels/${release}/include/generated/compile.h"
+      echo 'CONFIG_BPF=y' > "/usr/src/kernels/${release}/.config"
+      ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+      cp "/usr/src/kernels/${release}/.config" "/lib/modules/${release}/config"
+    os_release:
+      contents: |
+        NAME="BusyBox Linux"
+        ID=busybox
+`
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	defs, err := declarative.ParseDefinitions([]byte(testBusyBoxDefinitionYAML))
+	require.NoError(t, err)
+	os := defs[0].NewOperatingSystem(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5.10.0-1", "5.10.0-2"}, res.Names())
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	defs, err := declarative.ParseDefinitions([]byte(testBusyBoxDefinitionYAML))
+	require.NoError(t, err)
+	os := defs[0].NewOperatingSystem(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "5.10.0-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "busybox-linux", os.GetName())
+	assert.Equal(t, "busybox", res.OperatingSystem)
+	assert.Equal(t, "5.10.0-1-busybox", res.KernelRelease)
+	assert.Equal(t, "#3 SMP Mon Jan 2 00:00:00 UTC 2023", res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=busybox")
+	assert.Equal(t, "falco_busybox_5.10.0-1-busybox_3", res.ProbeName())
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", "grep CONFIG_BPF= /lib/modules/" + res.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				res.KernelSources:       "/usr/src/",
+				res.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
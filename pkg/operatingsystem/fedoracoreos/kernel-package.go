@@ -0,0 +1,102 @@
+package fedoracoreos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// TargetID is the target ID falco-driver-loader resolves for Fedora CoreOS hosts from the `ID` in their
+// `/etc/os-release`, which is shared with the rest of Fedora.
+const TargetID = "fedora"
+
+// NewKernelPackage returns a new hydrated Fedora CoreOS implementation of operatingsystem.KernelPackage for the given
+// kernel (as `<version>-<release>` of the kernel package, e.g. `6.2.9-300.fc37`).
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, name string) (*operatingsystem.KernelPackage, error) {
+	fedoraRelease, err := FedoraRelease(name)
+	if err != nil {
+		return nil, err
+	}
+
+	kernelDevelURL, err := KernelDevelURL(KojiPackagesURL, name)
+	if err != nil {
+		return nil, err
+	}
+
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: TargetID,
+		Name:            name,
+		KernelRelease:   name + "." + arch,
+		KernelMachine:   arch,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, kernelDevelURL); err != nil {
+		return nil, err
+	}
+
+	addOSRelease(kP, fedoraRelease)
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kP.KernelSources, kP.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelVersion = kernelVersion
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the kernel-devel package from Koji into the sources volume, as it would be
+// installed on a host, as well as the conventional `/lib/modules/<kernel release>/build` symlink and configuration in
+// the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, kernelDevelURL string) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	rpmDownloaderImage, err := BuildRPMDownloader(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build rpmdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+curl -sSfL -o kernel-devel.rpm %[2]s
+rpm2cpio kernel-devel.rpm | cpio --extract --make-directories --quiet
+cp -a ./usr/src/. /usr/src/
+mkdir -p /lib/modules/%[1]s
+ln -sfn /usr/src/kernels/%[1]s /lib/modules/%[1]s/build
+cp /usr/src/kernels/%[1]s/.config /lib/modules/%[1]s/config
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      rpmDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.KernelRelease, kernelDevelURL)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addOSRelease mocks the `/etc/os-release` of a Fedora CoreOS host as Fedora CoreOS is not published as a container
+// image. falco-driver-loader only reads the `ID` from it.
+func addOSRelease(kp *operatingsystem.KernelPackage, fedoraRelease string) {
+	kp.OSRelease = operatingsystem.FileContents(fmt.Sprintf(`NAME="Fedora Linux"
+ID=fedora
+VERSION_ID=%[1]s
+VARIANT="CoreOS"
+VARIANT_ID=coreos
+PRETTY_NAME="Fedora CoreOS %[1]s"
+`, fedoraRelease))
+}
@@ -0,0 +1,37 @@
+package fedoracoreos
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KojiPackagesURL is the base URL of the packages built by Fedora's Koji, which keeps every kernel ever shipped.
+const KojiPackagesURL = "https://kojipkgs.fedoraproject.org/packages/"
+
+// fedoraReleaseRe matches the Fedora release in the release of a kernel package, e.g. `300.fc37` -> 37.
+var fedoraReleaseRe = regexp.MustCompile(`\.fc([0-9]+)$`)
+
+// KernelDevelURL returns the URL of the kernel-devel package for the given kernel (as `<version>-<release>`),
+// e.g. `6.2.9-300.fc37` ->
+// `https://kojipkgs.fedoraproject.org/packages/kernel/6.2.9/300.fc37/x86_64/kernel-devel-6.2.9-300.fc37.x86_64.rpm`.
+func KernelDevelURL(kojiPackagesURL string, kernel string) (string, error) {
+	idx := strings.Index(kernel, "-")
+	if idx < 1 || idx == len(kernel)-1 {
+		return "", fmt.Errorf("could not split %s into a version and release", kernel)
+	}
+	version, release := kernel[:idx], kernel[idx+1:]
+
+	return fmt.Sprintf("%skernel/%s/%s/%s/kernel-devel-%s.%s.rpm", kojiPackagesURL, version, release, arch, kernel, arch), nil
+}
+
+// FedoraRelease returns the Fedora release the given kernel (as `<version>-<release>`) was built for, e.g.
+// `6.2.9-300.fc37` -> `37`.
+func FedoraRelease(kernel string) (string, error) {
+	matches := fedoraReleaseRe.FindStringSubmatch(kernel)
+	if matches == nil {
+		return "", fmt.Errorf("could not find the Fedora release of %s", kernel)
+	}
+
+	return matches[1], nil
+}
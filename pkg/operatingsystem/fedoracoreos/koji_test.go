@@ -0,0 +1,27 @@
+package fedoracoreos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/fedoracoreos"
+)
+
+func TestKernelDevelURL(t *testing.T) {
+	url, err := fedoracoreos.KernelDevelURL(fedoracoreos.KojiPackagesURL, "6.2.9-300.fc37")
+	require.NoError(t, err)
+	assert.Equal(t, "https://kojipkgs.fedoraproject.org/packages/kernel/6.2.9/300.fc37/x86_64/kernel-devel-6.2.9-300.fc37.x86_64.rpm", url)
+
+	_, err = fedoracoreos.KernelDevelURL(fedoracoreos.KojiPackagesURL, "6.2.9")
+	assert.Error(t, err)
+}
+
+func TestFedoraRelease(t *testing.T) {
+	release, err := fedoracoreos.FedoraRelease("6.2.9-300.fc37")
+	require.NoError(t, err)
+	assert.Equal(t, "37", release)
+
+	_, err = fedoracoreos.FedoraRelease("5.14.0-284.11.1.el9_2")
+	assert.Error(t, err)
+}
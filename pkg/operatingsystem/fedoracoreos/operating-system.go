@@ -0,0 +1,58 @@
+package fedoracoreos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system
+const Name = "fedoracoreos"
+
+// FedoraCoreOS implements operatingsystem.OperatingSystem for Fedora CoreOS.
+type FedoraCoreOS struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewFedoraCoreOS returns a new Fedora CoreOS implementation of operatingsystem.OperatingSystem.
+func NewFedoraCoreOS(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &FedoraCoreOS{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for fedoracoreos.
+func (s *FedoraCoreOS) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for fedoracoreos.
+// Note that KernelPackageNames in this context are the `<version>-<release>` of the kernel packages shipped in any
+// stream, e.g. `6.2.9-300.fc37`.
+func (s *FedoraCoreOS) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	kernels, err := ListKernels(ctx, StreamsURL, Streams)
+	if err != nil {
+		return nil, fmt.Errorf("could not list kernels: %w", err)
+	}
+
+	packageNames := []string{}
+	for _, kernel := range kernels {
+		if operatingsystem.IsEBPFCompatible(kernel) {
+			packageNames = append(packageNames, kernel)
+		}
+	}
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.KernelRelease = ref.Name + "." + arch
+		ref.Architecture = arch
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for fedoracoreos.
+func (s *FedoraCoreOS) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, name)
+}
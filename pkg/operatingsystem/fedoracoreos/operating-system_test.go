@@ -0,0 +1,43 @@
+package fedoracoreos_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/fedoracoreos"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := fedoracoreos.NewFedoraCoreOS(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.fc\d+$`), name)
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := fedoracoreos.NewFedoraCoreOS(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "6.2.9-300.fc37")
+	require.NoError(t, err)
+
+	assert.Equal(t, "fedora", res.OperatingSystem)
+	assert.Equal(t, "6.2.9-300.fc37.x86_64", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "VARIANT_ID=coreos")
+	assert.Equal(t, "falco_fedora_6.2.9-300.fc37.x86_64_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
@@ -0,0 +1,31 @@
+package fedoracoreos
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// RPMDownloaderDockerfile represents the contents of a Dockerfile to build the rpmdownloader image which downloads and
+// extracts RPM packages.
+const RPMDownloaderDockerfile = `FROM fedora:latest
+RUN dnf install -y cpio findutils \
+	&& dnf clean all
+`
+
+// RPMDownloaderRepository is the repository to build the rpmdownloader image under.
+const RPMDownloaderRepository = "docker.io/thoughtmachine/falco-rpmdownloader"
+
+// BuildRPMDownloader builds the rpmdownloader docker image.
+func BuildRPMDownloader(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:fedora", RPMDownloaderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: RPMDownloaderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -0,0 +1,177 @@
+package fedoracoreos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// StreamsURL is the base URL of Fedora CoreOS's stream metadata, which holds each stream's releases at
+	// `<stream>/releases.json` and each release's build metadata at `<stream>/builds/<version>/<arch>/`.
+	StreamsURL = "https://builds.coreos.fedoraproject.org/prod/streams/"
+
+	arch = "x86_64"
+
+	// maxConcurrentFetches is the maximum number of releases whose commit metadata is fetched at once.
+	maxConcurrentFetches = 8
+)
+
+var log = logging.Logger
+
+// Streams are the Fedora CoreOS streams whose kernels are listed.
+var Streams = []string{
+	"stable",
+	"testing",
+	"next",
+}
+
+type releasesIndex struct {
+	Releases []struct {
+		Version string `json:"version"`
+		Commits []struct {
+			Architecture string `json:"architecture"`
+		} `json:"commits"`
+	} `json:"releases"`
+}
+
+type commitMeta struct {
+	// PkgList is the list of packages in the release's OSTree commit, where each package is
+	// `[name, epoch, version, release, arch]`.
+	PkgList [][]string `json:"rpmostree.rpmdb.pkglist"`
+}
+
+// ListKernels returns the kernels (as `<version>-<release>` of the kernel package, e.g. `6.2.9-300.fc37`) shipped by
+// the releases of the given streams in the stream metadata at the given URL, in ascending order. Kernels shipped by
+// several releases or streams are only listed once as their probes are identical. Releases whose build metadata has
+// been pruned are logged and skipped rather than failing the whole listing.
+func ListKernels(ctx context.Context, streamsURL string, streams []string) ([]string, error) {
+	fetcher := fetch.New()
+
+	commitMetaURLs := []string{}
+	for _, stream := range streams {
+		releasesURL := fmt.Sprintf("%s%s/releases.json", streamsURL, stream)
+		body, err := fetcher.ReadAll(ctx, releasesURL)
+		if err != nil {
+			return nil, err
+		}
+
+		versions, err := ParseReleases(body)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", releasesURL, err)
+		}
+
+		for _, version := range versions {
+			commitMetaURLs = append(commitMetaURLs, fmt.Sprintf("%s%s/builds/%s/%s/commitmeta.json", streamsURL, stream, version, arch))
+		}
+	}
+
+	kernelSet, err := fetchKernels(ctx, fetcher, commitMetaURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	kernels := []string{}
+	for kernel := range kernelSet {
+		kernels = append(kernels, kernel)
+	}
+	sort.Strings(kernels)
+
+	return kernels, nil
+}
+
+// fetchKernels fetches the given commit metadata, at most maxConcurrentFetches at once, and returns the set of kernels
+// in them. Commit metadata which cannot be found is logged and skipped.
+func fetchKernels(ctx context.Context, fetcher *fetch.Fetcher, commitMetaURLs []string) (map[string]struct{}, error) {
+	sem := make(chan bool, maxConcurrentFetches)
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	kernelSet := map[string]struct{}{}
+	var firstErr error
+	for _, commitMetaURL := range commitMetaURLs {
+		wg.Add(1)
+		go func(commitMetaURL string) {
+			defer wg.Done()
+			sem <- true
+			defer func() { <-sem }()
+
+			kernel, err := fetchKernel(ctx, fetcher, commitMetaURL)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if fetch.IsNotFound(err) {
+					log.Warn().Err(err).Str("url", commitMetaURL).Msg("could not find commit metadata, skipping build")
+				} else if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			kernelSet[kernel] = struct{}{}
+		}(commitMetaURL)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return kernelSet, nil
+}
+
+// fetchKernel fetches the commit metadata at the given URL and returns the kernel in it.
+func fetchKernel(ctx context.Context, fetcher *fetch.Fetcher, commitMetaURL string) (string, error) {
+	body, err := fetcher.ReadAll(ctx, commitMetaURL)
+	if err != nil {
+		return "", err
+	}
+
+	kernel, err := ParseCommitMeta(body)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %s: %w", commitMetaURL, err)
+	}
+
+	return kernel, nil
+}
+
+// ParseReleases returns the versions of the x86_64 releases in the given releases index of a stream.
+func ParseReleases(releasesJSON []byte) ([]string, error) {
+	index := &releasesIndex{}
+	if err := json.Unmarshal(releasesJSON, index); err != nil {
+		return nil, err
+	}
+
+	versions := []string{}
+	for _, release := range index.Releases {
+		for _, commit := range release.Commits {
+			if commit.Architecture == arch {
+				versions = append(versions, release.Version)
+				break
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// ParseCommitMeta returns the kernel (as `<version>-<release>` of the kernel package) in the given commit metadata of a
+// release.
+func ParseCommitMeta(commitMetaJSON []byte) (string, error) {
+	meta := &commitMeta{}
+	if err := json.Unmarshal(commitMetaJSON, meta); err != nil {
+		return "", err
+	}
+
+	for _, pkg := range meta.PkgList {
+		if len(pkg) == 5 && pkg[0] == "kernel" {
+			return pkg[2] + "-" + pkg[3], nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find kernel in package list")
+}
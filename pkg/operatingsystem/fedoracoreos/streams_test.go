@@ -0,0 +1,82 @@
+package fedoracoreos_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/fedoracoreos"
+)
+
+// Excerpt of https://builds.coreos.fedoraproject.org/prod/streams/stable/releases.json
+const testReleasesJSON = `{
+  "releases": [
+    {
+      "commits": [
+        {"architecture": "x86_64", "checksum": "5d4a7b2a8dbd1ba3c0ad4a0c8ff0ed3e8a8f6a4bb2cdf5b3e8e2e5e2ad3a0f0e"},
+        {"architecture": "aarch64", "checksum": "0b8f1dd3c4a3b1e7cdbcd4d2b1f2a3c4d5e6f708192a3b4c5d6e7f8091a2b3c4"}
+      ],
+      "version": "37.20230401.3.0",
+      "metadata": "https://builds.coreos.fedoraproject.org/prod/streams/stable/builds/37.20230401.3.0/release.json"
+    },
+    {
+      "commits": [
+        {"architecture": "aarch64", "checksum": "1c9f2ee4d5b4c2f8dedce5e3c2f3b4d5e6f7081920a3b4c5d6e7f8091a2b3c4d"}
+      ],
+      "version": "37.20230402.3.0",
+      "metadata": "https://builds.coreos.fedoraproject.org/prod/streams/stable/builds/37.20230402.3.0/release.json"
+    }
+  ],
+  "metadata": {"last-modified": "2023-04-17T17:11:13Z"},
+  "stream": "stable"
+}`
+
+// Excerpt of https://builds.coreos.fedoraproject.org/prod/streams/stable/builds/37.20230401.3.0/x86_64/commitmeta.json
+const testCommitMetaJSON = `{
+  "ostree.bootable": true,
+  "rpmostree.rpmdb.pkglist": [
+    ["NetworkManager", "1", "1.40.16", "1.fc37", "x86_64"],
+    ["kernel", "0", "6.2.9", "300.fc37", "x86_64"],
+    ["kernel-core", "0", "6.2.9", "300.fc37", "x86_64"]
+  ]
+}`
+
+func TestParseReleases(t *testing.T) {
+	versions, err := fedoracoreos.ParseReleases([]byte(testReleasesJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"37.20230401.3.0"}, versions)
+}
+
+func TestParseCommitMeta(t *testing.T) {
+	kernel, err := fedoracoreos.ParseCommitMeta([]byte(testCommitMetaJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "6.2.9-300.fc37", kernel)
+
+	_, err = fedoracoreos.ParseCommitMeta([]byte(`{"rpmostree.rpmdb.pkglist": []}`))
+	assert.Error(t, err)
+}
+
+func TestListKernels(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stable/releases.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testReleasesJSON))
+	})
+	mux.HandleFunc("/stable/builds/37.20230401.3.0/x86_64/commitmeta.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testCommitMetaJSON))
+	})
+	mux.HandleFunc("/testing/releases.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"releases": [{"version": "38.20230410.2.0", "commits": [{"architecture": "x86_64"}]}]}`))
+	})
+	// The testing release's build has been pruned so its commit metadata is not found.
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	kernels, err := fedoracoreos.ListKernels(context.Background(), server.URL+"/", []string{"stable", "testing"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"6.2.9-300.fc37"}, kernels)
+}
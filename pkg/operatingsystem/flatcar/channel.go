@@ -0,0 +1,58 @@
+package flatcar
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Channel represents a Flatcar release channel. Each channel has its own release cadence and releases, so each channel
+// is its own operating system to allow building probes for only the channels in use.
+type Channel struct {
+	// Name is the name of this channel, e.g. `stable`.
+	Name string
+}
+
+var (
+	// Stable is the channel of Flatcar releases recommended for production.
+	Stable = &Channel{Name: "stable"}
+	// Beta is the channel of Flatcar releases which are promoted to Stable.
+	Beta = &Channel{Name: "beta"}
+	// Alpha is the channel of Flatcar releases which are promoted to Beta.
+	Alpha = &Channel{Name: "alpha"}
+	// LTS is the channel of Flatcar releases which are supported for 18 months.
+	LTS = &Channel{Name: "lts"}
+)
+
+// Support returns the support status of the releases in this channel.
+func (c *Channel) Support() operatingsystem.Support {
+	if c == LTS {
+		return operatingsystem.SupportLTS
+	}
+
+	return operatingsystem.SupportActive
+}
+
+// OperatingSystemName returns the name of the operating system for this channel, e.g. `flatcar-stable`.
+func (c *Channel) OperatingSystemName() string {
+	return Name + "-" + c.Name
+}
+
+// NewFlatcar returns a new flatcar implementation of operatingsystem.OperatingSystem for this channel.
+func (c *Channel) NewFlatcar(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Flatcar{
+		dockerClient: dockerClient,
+		channel:      c,
+	}
+}
+
+// ReleasesURL returns the URL of the JSON document describing this channel's releases.
+func (c *Channel) ReleasesURL() string {
+	return fmt.Sprintf("https://www.flatcar.org/releases-json/releases-%s.json", c.Name)
+}
+
+// releaseURL returns the URL of the directory containing the artifacts of the given release in this channel.
+func (c *Channel) releaseURL(version string) string {
+	return fmt.Sprintf("https://%s.release.flatcar-linux.net/amd64-usr/%s/", c.Name, version)
+}
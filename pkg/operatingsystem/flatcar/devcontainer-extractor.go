@@ -0,0 +1,34 @@
+package flatcar
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// DevContainerExtractorDockerfile represents the contents of a Dockerfile to build the devcontainerextractor image
+// which extracts and prepares the kernel sources from Flatcar's developer container image. The developer container is
+// a disk image rather than a Docker image, so we use 7-Zip to extract its filesystem without mounting it, and the
+// kernel build dependencies to prepare its kernel sources.
+const DevContainerExtractorDockerfile = `FROM ubuntu:24.04
+RUN apt-get update \
+	&& apt-get install -y 7zip bc bison build-essential bzip2 ca-certificates curl flex libelf-dev libssl-dev \
+	&& rm -rf /var/lib/apt/lists/*
+`
+
+// DevContainerExtractorRepository is the repository to build the devcontainerextractor image under.
+const DevContainerExtractorRepository = "docker.io/thoughtmachine/falco-devcontainerextractor"
+
+// BuildDevContainerExtractor builds the devcontainerextractor docker image.
+func BuildDevContainerExtractor(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:latest", DevContainerExtractorRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: DevContainerExtractorDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -0,0 +1,107 @@
+package flatcar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const (
+	kernelMachine = "x86_64"
+	// kernelVersion is the `uname -v` of Flatcar kernels. Flatcar kernels are only built once per release so their
+	// build number is always 1, and falco-driver-loader reads nothing else from it.
+	kernelVersion = "#1 SMP"
+)
+
+// NewKernelPackage returns a new hydrated Flatcar implementation of operatingsystem.KernelPackage for the given Release.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, release *Release) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            release.Name(),
+		KernelVersion:   kernelVersion,
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, release); err != nil {
+		return nil, err
+	}
+
+	addOSRelease(kP, release)
+
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kP.KernelSources)
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelRelease = kernelRelease
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the kernel sources from the release's developer container, prepares them with
+// the release's kernel configuration and copies them into the sources volume at `/usr/src/kernels/<kernel release>`,
+// where falco-driver-builder expects to find them, as well as the conventional `/lib/modules/<kernel release>/build`
+// symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, release *Release) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	devContainerExtractorImage, err := BuildDevContainerExtractor(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build devcontainerextractor: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+curl -sSfL %[1]sflatcar_developer_container.bin.bz2 | bunzip2 > developer_container.bin
+curl -sSfL -o config %[1]sflatcar_production_image_kernel_config.txt
+7zz x -y -odisk developer_container.bin > /dev/null
+# The developer container is a partitioned disk image; its root filesystem is the largest partition.
+if [ ! -d disk/usr ]; then
+	rootfs="$(find disk -maxdepth 1 -type f -printf '%%s %%p\n' | sort -rn | head -n1 | cut -d' ' -f2)"
+	7zz x -y -orootfs "${rootfs}" 'usr/src/*' > /dev/null
+else
+	mv disk rootfs
+fi
+src="$(find rootfs/usr/src -mindepth 1 -maxdepth 1 -type d -name 'linux-*' | sort -V | tail -n1)"
+cp config "${src}/.config"
+make -s -C "${src}" olddefconfig modules_prepare
+release="$(make -s -C "${src}" kernelrelease)"
+mkdir -p /usr/src/kernels "/lib/modules/${release}"
+cp -a "${src}" "/usr/src/kernels/${release}"
+ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+cp config "/lib/modules/${release}/config"
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      devContainerExtractorImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, release.URL())},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addOSRelease mocks the `/etc/os-release` of a Flatcar host as Flatcar do not publish a container image of their
+// operating system. falco-driver-loader reads the `ID` and `VERSION_ID` from it.
+func addOSRelease(kp *operatingsystem.KernelPackage, release *Release) {
+	kp.OSRelease = operatingsystem.FileContents(fmt.Sprintf(`NAME="Flatcar Container Linux by Kinvolk"
+ID=flatcar
+ID_LIKE=coreos
+VERSION=%[1]s
+VERSION_ID=%[1]s
+PRETTY_NAME="Flatcar Container Linux by Kinvolk %[1]s"
+`, release.Version))
+}
@@ -0,0 +1,36 @@
+package flatcar_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/flatcar"
+)
+
+func TestGetKernelSourcesAndConfiguration(t *testing.T) {
+	cli := docker.MustClient()
+
+	kp, err := flatcar.NewKernelPackage(context.Background(), cli, &flatcar.Release{Channel: flatcar.Stable, Version: "3510.2.0", Kernel: "5.15.106"})
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/" + kp.KernelRelease + "/include/generated/autoconf.h && " +
+				"ls /lib/modules/" + kp.KernelRelease + "/build && " +
+				"grep CONFIG_BPF= /lib/modules/" + kp.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
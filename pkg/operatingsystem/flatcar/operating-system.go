@@ -0,0 +1,87 @@
+package flatcar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system, which is also the target ID falco-driver-loader resolves for all
+// Flatcar channels.
+const Name = "flatcar"
+
+// Flatcar implements operatingsystem.OperatingSystem for a channel of Flatcar Container Linux.
+type Flatcar struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	channel      *Channel
+
+	// releases caches the listing of the channel's releases as the kernel package name alone is not enough to know
+	// the release's kernel.
+	releases     map[string]*Release
+	releasesLock sync.Mutex
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for flatcar.
+func (s *Flatcar) GetName() string {
+	return s.channel.OperatingSystemName()
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for flatcar.
+// Note that KernelPackageNames in this context are channel releases, e.g. `flatcar-stable-3510.2.0`.
+func (s *Flatcar) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	releases, err := s.getReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packageNames := []string{}
+	for name, release := range releases {
+		if operatingsystem.IsEBPFCompatible(release.Kernel) {
+			packageNames = append(packageNames, name)
+		}
+	}
+	sort.Strings(packageNames)
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.Architecture = kernelMachine
+		ref.Published = releases[ref.Name].Published
+		ref.Repository = s.channel.Name
+		ref.Support = s.channel.Support()
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for flatcar.
+func (s *Flatcar) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	releases, err := s.getReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	release, ok := releases[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find release for %s in %s", name, s.channel.ReleasesURL())
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, release)
+}
+
+func (s *Flatcar) getReleases(ctx context.Context) (map[string]*Release, error) {
+	s.releasesLock.Lock()
+	defer s.releasesLock.Unlock()
+
+	if s.releases == nil {
+		releases, err := ListReleases(ctx, s.channel)
+		if err != nil {
+			return nil, fmt.Errorf("could not list releases: %w", err)
+		}
+		s.releases = releases
+	}
+
+	return s.releases, nil
+}
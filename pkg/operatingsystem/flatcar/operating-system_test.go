@@ -0,0 +1,49 @@
+package flatcar_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/flatcar"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+
+	for _, channel := range []*flatcar.Channel{flatcar.Stable, flatcar.Beta, flatcar.Alpha, flatcar.LTS} {
+		channel := channel
+		t.Run(channel.Name, func(t *testing.T) {
+			os := channel.NewFlatcar(cli)
+
+			res, err := os.GetKernelPackageNames(context.Background())
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, res)
+			for _, name := range res.Names() {
+				assert.Regexp(t, regexp.MustCompile(`^flatcar-`+channel.Name+`-\d+\.\d+\.\d+$`), name)
+			}
+		})
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := flatcar.Stable.NewFlatcar(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "flatcar-stable-3510.2.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "flatcar", res.OperatingSystem)
+	assert.Equal(t, "5.15.106-flatcar", res.KernelRelease)
+	assert.Equal(t, "#1 SMP", res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=flatcar")
+	assert.Equal(t, "falco_flatcar_3510.2.0_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
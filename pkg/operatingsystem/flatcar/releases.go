@@ -0,0 +1,110 @@
+package flatcar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// releaseDateLayout is the layout of the release dates in the releases JSON, e.g. `2023-05-16 13:11:32 +0000`.
+	releaseDateLayout = "2006-01-02 15:04:05 -0700"
+)
+
+// releaseVersionRe matches Flatcar release versions, e.g. `3510.2.0`.
+var releaseVersionRe = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// Release represents a Flatcar release in a channel.
+type Release struct {
+	// Channel is the channel of the release.
+	Channel *Channel
+	// Version is the version of the release, e.g. `3510.2.0`.
+	Version string
+	// Kernel is the upstream version of the kernel shipped in the release, e.g. `5.15.106`.
+	Kernel string
+	// Published is when the release was published, or the zero time if unknown.
+	Published time.Time
+}
+
+// Name returns the kernel package name of the Release, e.g. `flatcar-stable-3510.2.0`.
+func (r *Release) Name() string {
+	return fmt.Sprintf("%s-%s", r.Channel.OperatingSystemName(), r.Version)
+}
+
+// URL returns the URL of the directory containing the artifacts of the Release.
+func (r *Release) URL() string {
+	return r.Channel.releaseURL(r.Version)
+}
+
+type releaseMetadata struct {
+	Architectures []string `json:"architectures"`
+	ReleaseDate   string   `json:"release_date"`
+	MajorSoftware struct {
+		Kernel []string `json:"kernel"`
+	} `json:"major_software"`
+}
+
+// ListReleases returns the releases of the given channel, keyed by kernel package name.
+func ListReleases(ctx context.Context, channel *Channel) (map[string]*Release, error) {
+	url := channel.ReleasesURL()
+	body, err := fetch.New().ReadAll(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := ParseReleases(channel, body)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", url, err)
+	}
+
+	return releases, nil
+}
+
+// ParseReleases returns the amd64 releases of the given channel described by the given releases JSON document, keyed
+// by kernel package name. The document also describes the `current` release under that key which is ignored as it is
+// a duplicate of its versioned entry.
+func ParseReleases(channel *Channel, releasesJSON []byte) (map[string]*Release, error) {
+	metadata := map[string]*releaseMetadata{}
+	if err := json.Unmarshal(releasesJSON, &metadata); err != nil {
+		return nil, err
+	}
+
+	releases := map[string]*Release{}
+	for version, meta := range metadata {
+		if !releaseVersionRe.MatchString(version) || !hasArchitecture(meta, "amd64") || len(meta.MajorSoftware.Kernel) < 1 {
+			continue
+		}
+
+		release := &Release{
+			Channel: channel,
+			Version: version,
+			Kernel:  meta.MajorSoftware.Kernel[0],
+		}
+		if published, err := time.Parse(releaseDateLayout, meta.ReleaseDate); err == nil {
+			release.Published = published.UTC()
+		}
+		releases[release.Name()] = release
+	}
+
+	return releases, nil
+}
+
+// hasArchitecture returns whether the release described by the given metadata is built for the given architecture.
+// Releases from before Flatcar supported arm64 do not list their architectures as they were only built for amd64.
+func hasArchitecture(meta *releaseMetadata, arch string) bool {
+	if len(meta.Architectures) == 0 {
+		return arch == "amd64"
+	}
+
+	for _, a := range meta.Architectures {
+		if a == arch {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package flatcar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/flatcar"
+)
+
+// Excerpt of https://www.flatcar.org/releases-json/releases-stable.json
+const testReleasesJSON = `{
+  "current": {
+    "channel": "stable",
+    "architectures": ["amd64", "arm64"],
+    "release_date": "2023-05-16 13:11:32 +0000",
+    "major_software": {"kernel": ["5.15.111"], "docker": ["20.10.23"]}
+  },
+  "3510.2.1": {
+    "channel": "stable",
+    "architectures": ["amd64", "arm64"],
+    "release_date": "2023-05-16 13:11:32 +0000",
+    "major_software": {"kernel": ["5.15.111"], "docker": ["20.10.23"]}
+  },
+  "3510.2.0": {
+    "channel": "stable",
+    "architectures": ["amd64", "arm64"],
+    "release_date": "2023-04-26 10:22:56 +0000",
+    "major_software": {"kernel": ["5.15.106"], "docker": ["20.10.23"]}
+  },
+  "2345.3.0": {
+    "channel": "stable",
+    "release_date": "2020-03-02 13:11:32 +0000",
+    "major_software": {"kernel": ["4.19.106"], "docker": ["18.06.3"]}
+  },
+  "9999.0.0": {
+    "channel": "stable",
+    "architectures": ["arm64"],
+    "release_date": "2023-05-16 13:11:32 +0000",
+    "major_software": {"kernel": ["5.15.111"], "docker": ["20.10.23"]}
+  }
+}`
+
+func TestParseReleases(t *testing.T) {
+	releases, err := flatcar.ParseReleases(flatcar.Stable, []byte(testReleasesJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]*flatcar.Release{
+		"flatcar-stable-3510.2.1": {
+			Channel: flatcar.Stable, Version: "3510.2.1", Kernel: "5.15.111",
+			Published: time.Date(2023, time.May, 16, 13, 11, 32, 0, time.UTC),
+		},
+		"flatcar-stable-3510.2.0": {
+			Channel: flatcar.Stable, Version: "3510.2.0", Kernel: "5.15.106",
+			Published: time.Date(2023, time.April, 26, 10, 22, 56, 0, time.UTC),
+		},
+		"flatcar-stable-2345.3.0": {
+			Channel: flatcar.Stable, Version: "2345.3.0", Kernel: "4.19.106",
+			Published: time.Date(2020, time.March, 2, 13, 11, 32, 0, time.UTC),
+		},
+	}, releases)
+}
+
+func TestReleaseURL(t *testing.T) {
+	release := &flatcar.Release{Channel: flatcar.LTS, Version: "3033.3.16"}
+
+	assert.Equal(t, "flatcar-lts-3033.3.16", release.Name())
+	assert.Equal(t, "https://lts.release.flatcar-linux.net/amd64-usr/3033.3.16/", release.URL())
+}
@@ -0,0 +1,112 @@
+package operatingsystem
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Support represents the support status of the release which a kernel package belongs to.
+type Support string
+
+const (
+	// SupportUnknown is the Support of kernel packages whose support status is not known.
+	SupportUnknown Support = ""
+	// SupportActive is the Support of kernel packages belonging to a supported release which is not long-term supported.
+	SupportActive Support = "active"
+	// SupportLTS is the Support of kernel packages belonging to a long-term supported release.
+	SupportLTS Support = "lts"
+	// SupportEOL is the Support of kernel packages belonging to a release which is no longer supported.
+	SupportEOL Support = "eol"
+)
+
+// KernelPackageRef references an available Kernel Package along with what is known about it without hydrating it.
+// Only the Name is always set, the other fields are left as their zero values when they are not known.
+type KernelPackageRef struct {
+	// Name is the name of the KernelPackage from the Operating System's perspective, as given to GetKernelPackageByName.
+	Name string `json:"name"`
+	// KernelRelease is the `uname -r` of the kernel package's kernel.
+	KernelRelease string `json:"kernel_release,omitempty"`
+	// Architecture is the `uname -m` of the kernel package's kernel.
+	Architecture string `json:"architecture,omitempty"`
+	// Published is when the kernel package was published.
+	Published time.Time `json:"published"`
+	// Repository is the repository, channel or stream which the kernel package is published in.
+	Repository string `json:"repository,omitempty"`
+	// Support is the support status of the release which the kernel package belongs to.
+	Support Support `json:"support,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler for KernelPackageRef, omitting Published when it is not known as `omitempty`
+// does not apply to structs such as time.Time.
+func (ref KernelPackageRef) MarshalJSON() ([]byte, error) {
+	// kernelPackageRef does not have the methods of KernelPackageRef so that marshalling it does not recurse.
+	type kernelPackageRef KernelPackageRef
+
+	var published *time.Time
+	if !ref.Published.IsZero() {
+		published = &ref.Published
+	}
+
+	return json.Marshal(struct {
+		kernelPackageRef
+		Published *time.Time `json:"published,omitempty"`
+	}{kernelPackageRef(ref), published})
+}
+
+// KernelPackageRefs represents a list of KernelPackageRef.
+type KernelPackageRefs []KernelPackageRef
+
+// NewKernelPackageRefs returns KernelPackageRefs for the given names, with the given function filling in what is known
+// about each of them (which may be nil if nothing is).
+func NewKernelPackageRefs(names []string, describe func(ref *KernelPackageRef)) KernelPackageRefs {
+	refs := KernelPackageRefs{}
+	for _, name := range names {
+		ref := KernelPackageRef{Name: name}
+		if describe != nil {
+			describe(&ref)
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// Names returns the names of the KernelPackageRefs.
+func (refs KernelPackageRefs) Names() []string {
+	names := []string{}
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	return names
+}
+
+// PublishedSince returns the KernelPackageRefs which were published at or after the given time. KernelPackageRefs
+// whose publish date is not known are kept.
+func (refs KernelPackageRefs) PublishedSince(since time.Time) KernelPackageRefs {
+	filtered := KernelPackageRefs{}
+	for _, ref := range refs {
+		if !ref.Published.IsZero() && ref.Published.Before(since) {
+			continue
+		}
+		filtered = append(filtered, ref)
+	}
+
+	return filtered
+}
+
+// NewestFirst returns the KernelPackageRefs sorted by their publish date, newest first, followed by those whose
+// publish date is not known in their original order.
+func (refs KernelPackageRefs) NewestFirst() KernelPackageRefs {
+	sorted := append(KernelPackageRefs{}, refs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[j].Published.IsZero() {
+			return !sorted[i].Published.IsZero()
+		}
+
+		return sorted[i].Published.After(sorted[j].Published)
+	})
+
+	return sorted
+}
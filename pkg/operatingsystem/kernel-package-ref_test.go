@@ -0,0 +1,61 @@
+package operatingsystem_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+var (
+	oldRef     = operatingsystem.KernelPackageRef{Name: "old", Published: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), Support: operatingsystem.SupportEOL}
+	newRef     = operatingsystem.KernelPackageRef{Name: "new", Published: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Support: operatingsystem.SupportLTS}
+	newerRef   = operatingsystem.KernelPackageRef{Name: "newer", Published: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC), Support: operatingsystem.SupportActive}
+	unknownRef = operatingsystem.KernelPackageRef{Name: "unknown"}
+	testRefs   = operatingsystem.KernelPackageRefs{oldRef, unknownRef, newRef, newerRef}
+)
+
+func TestNewKernelPackageRefs(t *testing.T) {
+	assert.Equal(t, operatingsystem.KernelPackageRefs{{Name: "a"}, {Name: "b"}}, operatingsystem.NewKernelPackageRefs([]string{"a", "b"}, nil))
+	assert.Equal(t,
+		operatingsystem.KernelPackageRefs{{Name: "a", KernelRelease: "a.x86_64"}},
+		operatingsystem.NewKernelPackageRefs([]string{"a"}, func(ref *operatingsystem.KernelPackageRef) {
+			ref.KernelRelease = ref.Name + ".x86_64"
+		}),
+	)
+}
+
+func TestKernelPackageRefsNames(t *testing.T) {
+	assert.Equal(t, []string{"old", "unknown", "new", "newer"}, testRefs.Names())
+	assert.Equal(t, []string{}, operatingsystem.KernelPackageRefs{}.Names())
+}
+
+func TestKernelPackageRefsPublishedSince(t *testing.T) {
+	assert.Equal(t,
+		operatingsystem.KernelPackageRefs{unknownRef, newRef, newerRef},
+		testRefs.PublishedSince(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)),
+	)
+	assert.Equal(t,
+		operatingsystem.KernelPackageRefs{unknownRef},
+		testRefs.PublishedSince(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)),
+	)
+}
+
+func TestKernelPackageRefMarshalJSON(t *testing.T) {
+	newJSON, err := json.Marshal(newRef)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "new", "published": "2023-01-01T00:00:00Z", "support": "lts"}`, string(newJSON))
+
+	unknownJSON, err := json.Marshal(unknownRef)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "unknown"}`, string(unknownJSON))
+}
+
+func TestKernelPackageRefsNewestFirst(t *testing.T) {
+	assert.Equal(t, operatingsystem.KernelPackageRefs{newerRef, newRef, oldRef, unknownRef}, testRefs.NewestFirst())
+	// the original order is left untouched
+	assert.Equal(t, []string{"old", "unknown", "new", "newer"}, testRefs.Names())
+}
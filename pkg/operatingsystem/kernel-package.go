@@ -3,6 +3,7 @@ package operatingsystem
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -26,7 +27,16 @@ type KernelPackage struct {
 	KernelSources Volume
 }
 
-var kernelVersionRe = regexp.MustCompile(`^#(\d+)`)
+var (
+	kernelVersionRe = regexp.MustCompile(`^#(\d+)`)
+	// ubuntuKernelVersionRe matches the kernel version of an Ubuntu hardware enablement (HWE) or backported kernel in
+	// `uname -v`, e.g. `66~20.04.1`.
+	ubuntuKernelVersionRe = regexp.MustCompile(`^#([0-9]+~[^-\\ ]*)-Ubuntu `)
+	// debianArchRe matches the flavour and architecture of a Debian kernel release, e.g. `-cloud-amd64`.
+	debianArchRe = regexp.MustCompile(`-?(rt-|cloud-|)(amd64|arm64)`)
+	// debianPackageVersionRe matches the version of the Debian kernel package in `uname -v`, e.g. `6.1.76-1`.
+	debianPackageVersionRe = regexp.MustCompile(`([0-9]+\.[0-9]+\.[0-9]+-[0-9]+)`)
+)
 
 // ProbeName returns the ProbeName expected by Falco.
 // interpreted from: https://github.com/falcosecurity/falco/blob/0.29.1/scripts/falco-driver-loader#L449
@@ -34,6 +44,24 @@ func (kp *KernelPackage) ProbeName() string {
 	driverName := "falco"
 	targetID := kp.OperatingSystem
 	kernelRelease := kp.KernelRelease
+	// from: KERNEL_RELEASE="${VERSION_ID}"
+	// falco-driver-loader (>= 0.32.0) uses the Flatcar release rather than the kernel release for Flatcar.
+	if targetID == "flatcar" {
+		kernelRelease = OSReleaseValue(kp.OSRelease, "VERSION_ID")
+	}
+	// from: KERNEL_RELEASE="${BASH_REMATCH[1]}${ARCH_extra}"
+	// falco-driver-loader (>= 0.32.0) uses the version of the Debian kernel package in `uname -v` followed by the
+	// flavour and architecture of the kernel release for Debian, as Debian kernel releases are only their ABI, e.g.
+	// `6.1.0-18-amd64` and `#1 SMP PREEMPT_DYNAMIC Debian 6.1.76-1 (2024-02-01)` become `6.1.76-1-amd64`.
+	if targetID == "debian" {
+		archExtra := ""
+		if matches := debianArchRe.FindStringSubmatch(kernelRelease); matches != nil {
+			archExtra = "-" + matches[1] + matches[2]
+		}
+		if matches := debianPackageVersionRe.FindStringSubmatch(kp.KernelVersion); matches != nil {
+			kernelRelease = matches[1] + archExtra
+		}
+	}
 	// from: $(uname -v | sed 's/#\([[:digit:]]\+\).*/\1/')
 	// this sed command is extracting the first set of digits from the KernelVersion after the #. e.g.
 	// `#151-Ubuntu SMP Fri Jun 18 19:21:19 UTC 2021` becomes `151`.
@@ -42,10 +70,89 @@ func (kp *KernelPackage) ProbeName() string {
 	if len(matches) == 2 {
 		kernelVersion = matches[1]
 	}
+	// from: KERNEL_VERSION=$(uname -v | sed 's/#\([^-\\ ]*\).*/\1/g')
+	// falco-driver-loader keeps the Ubuntu release of HWE and backported kernels in the kernel version for Ubuntu, e.g.
+	// `#66~20.04.1-Ubuntu SMP Fri Jan 20 19:50:59 UTC 2023` becomes `66~20.04.1`.
+	if strings.HasPrefix(targetID, "ubuntu") {
+		if matches := ubuntuKernelVersionRe.FindStringSubmatch(kp.KernelVersion); matches != nil {
+			kernelVersion = matches[1]
+		}
+	}
+	// from: KERNEL_VERSION="1_${VERSION_ID}-${VARIANT_ID}"
+	// falco-driver-loader (>= 0.33.0) appends the Bottlerocket release and the first component of the variant (e.g.
+	// `aws` of `aws-k8s-1.24`) to the kernel version for Bottlerocket, e.g. `1_1.13.0-aws`.
+	if targetID == "bottlerocket" {
+		variantFamily := strings.SplitN(OSReleaseValue(kp.OSRelease, "VARIANT_ID"), "-", 2)[0]
+		kernelVersion = kernelVersion + "_" + OSReleaseValue(kp.OSRelease, "VERSION_ID") + "-" + variantFamily
+	}
 
 	return fmt.Sprintf("%s_%s_%s_%s", driverName, targetID, kernelRelease, kernelVersion)
 }
 
+// targetMinimumFalcoVersions are the oldest Falco versions whose falco-driver-loader names probes for each target ID as
+// ProbeName does, for the target IDs whose probe names changed. Older Falco versions name the probes they build
+// otherwise, so they would never be found as already mirrored.
+var targetMinimumFalcoVersions = map[string]string{
+	"bottlerocket": "0.33.0",
+	"debian":       "0.32.0",
+	"flatcar":      "0.32.0",
+}
+
+// MinimumFalcoVersion returns the oldest Falco version which builds a probe named ProbeName for the KernelPackage, or an
+// empty string if every Falco version does.
+func (kp *KernelPackage) MinimumFalcoVersion() string {
+	minimumFalcoVersion := machineMinimumFalcoVersions[kp.KernelMachine]
+	if targetMinimumFalcoVersion, ok := targetMinimumFalcoVersions[kp.OperatingSystem]; ok && compareFalcoVersions(targetMinimumFalcoVersion, minimumFalcoVersion) > 0 {
+		minimumFalcoVersion = targetMinimumFalcoVersion
+	}
+
+	return minimumFalcoVersion
+}
+
+// SupportsFalcoVersion returns whether the given Falco version, e.g. `0.33.0`, builds a probe for the KernelPackage.
+func (kp *KernelPackage) SupportsFalcoVersion(falcoVersion string) bool {
+	minimumFalcoVersion := kp.MinimumFalcoVersion()
+
+	return minimumFalcoVersion == "" || compareFalcoVersions(falcoVersion, minimumFalcoVersion) >= 0
+}
+
+// compareFalcoVersions returns -1, 0 or 1 if the Falco version a is older than, the same as or newer than b.
+func compareFalcoVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := 0, 0
+		if i < len(aParts) {
+			aPart, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bPart, _ = strconv.Atoi(bParts[i])
+		}
+		if aPart < bPart {
+			return -1
+		}
+		if aPart > bPart {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+var osReleaseLineRe = regexp.MustCompile(`^([A-Z_]+)=(.*)$`)
+
+// OSReleaseValue returns the unquoted value of the given key in the given `/etc/os-release` contents.
+func OSReleaseValue(osRelease FileContents, key string) string {
+	for _, line := range strings.Split(string(osRelease), "\n") {
+		matches := osReleaseLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) == 3 && matches[1] == key {
+			return strings.Trim(matches[2], `"'`)
+		}
+	}
+
+	return ""
+}
+
 // FileContents represents the contents of a file.
 type FileContents string
 
@@ -20,6 +20,14 @@ func TestKernelPackageProbeName(t *testing.T) {
 			},
 			"falco_ubuntu_4.15.0-147-generic_151",
 		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "ubuntu-generic",
+				KernelRelease:   "5.15.0-60-generic",
+				KernelVersion:   "#66~20.04.1-Ubuntu SMP Wed Jan 25 09:41:30 UTC 2023",
+			},
+			"falco_ubuntu-generic_5.15.0-60-generic_66~20.04.1",
+		},
 		{
 			&operatingsystem.KernelPackage{
 				OperatingSystem: "amazonlinux2",
@@ -36,6 +44,58 @@ func TestKernelPackageProbeName(t *testing.T) {
 			},
 			"falco_cos_5.15.65_1",
 		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "flatcar",
+				KernelRelease:   "5.15.106-flatcar",
+				KernelVersion:   "#1 SMP",
+				OSRelease:       "NAME=\"Flatcar Container Linux by Kinvolk\"\nID=flatcar\nVERSION_ID=3510.2.0\n",
+			},
+			"falco_flatcar_3510.2.0_1",
+		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "debian",
+				KernelRelease:   "6.1.0-18-amd64",
+				KernelVersion:   "#1 SMP PREEMPT_DYNAMIC Debian 6.1.76-1 (2024-02-01)",
+			},
+			"falco_debian_6.1.76-1-amd64_1",
+		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "debian",
+				KernelRelease:   "6.1.0-18-cloud-amd64",
+				KernelVersion:   "#1 SMP PREEMPT_DYNAMIC Debian 6.1.76-1 (2024-02-01)",
+			},
+			"falco_debian_6.1.76-1-cloud-amd64_1",
+		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "debian",
+				KernelRelease:   "6.4.0-0.deb12.2-rt-amd64",
+				KernelVersion:   "#1 SMP PREEMPT_RT Debian 6.4.4-3~bpo12+1 (2023-08-08)",
+			},
+			"falco_debian_6.4.4-3-rt-amd64_1",
+		},
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "bottlerocket",
+				KernelRelease:   "5.10.165",
+				KernelVersion:   "#1 SMP Wed Mar 1 20:58:49 UTC 2023",
+				OSRelease:       "NAME=Bottlerocket\nID=bottlerocket\nVERSION_ID=1.13.0\nVARIANT_ID=aws-k8s-1.23\n",
+			},
+			"falco_bottlerocket_5.10.165_1_1.13.0-aws",
+		},
+		// variants of the same family and release may ship different kernels.
+		{
+			&operatingsystem.KernelPackage{
+				OperatingSystem: "bottlerocket",
+				KernelRelease:   "5.15.90",
+				KernelVersion:   "#1 SMP Wed Mar 1 20:58:49 UTC 2023",
+				OSRelease:       "NAME=Bottlerocket\nID=bottlerocket\nVERSION_ID=1.13.0\nVARIANT_ID=aws-k8s-1.24\n",
+			},
+			"falco_bottlerocket_5.15.90_1_1.13.0-aws",
+		},
 	}
 
 	for _, tt := range tests {
@@ -45,3 +105,33 @@ func TestKernelPackageProbeName(t *testing.T) {
 		})
 	}
 }
+
+func TestKernelPackageSupportsFalcoVersion(t *testing.T) {
+	var tests = []struct {
+		operatingSystem string
+		kernelMachine   string
+		falcoVersion    string
+		supported       bool
+	}{
+		{"amazonlinux2", "x86_64", "0.24.0", true},
+		{"amazonlinux2", "x86_64", "0.33.0", true},
+		{"amazonlinux2", "", "0.24.0", true},
+		{"amazonlinux2", "aarch64", "0.24.0", false},
+		{"amazonlinux2", "aarch64", "0.30.0", false},
+		{"amazonlinux2", "aarch64", "0.31.1", true},
+		{"amazonlinux2", "aarch64", "0.33.0", true},
+		{"bottlerocket", "x86_64", "0.31.1", false},
+		{"bottlerocket", "x86_64", "0.33.0", true},
+		{"debian", "x86_64", "0.31.1", false},
+		{"debian", "x86_64", "0.33.0", true},
+		{"flatcar", "x86_64", "0.31.1", false},
+		{"flatcar", "x86_64", "0.33.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operatingSystem+"-"+tt.kernelMachine+"-"+tt.falcoVersion, func(t *testing.T) {
+			kp := &operatingsystem.KernelPackage{OperatingSystem: tt.operatingSystem, KernelMachine: tt.kernelMachine}
+			assert.Equal(t, tt.supported, kp.SupportsFalcoVersion(tt.falcoVersion))
+		})
+	}
+}
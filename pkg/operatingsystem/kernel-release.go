@@ -0,0 +1,32 @@
+package operatingsystem
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var kernelMajorMinorRe = regexp.MustCompile(`^([0-9]+)\.([0-9]+)`)
+
+// ebpfMinKernelMajor and ebpfMinKernelMinor represent the oldest kernel <major>.<minor> that Falco's eBPF probe supports.
+const (
+	ebpfMinKernelMajor = 4
+	ebpfMinKernelMinor = 14
+)
+
+// IsEBPFCompatible returns whether or not the given kernel release (or kernel package name beginning with the
+// kernel release) is recent enough to build a Falco eBPF probe for (>= 4.14).
+func IsEBPFCompatible(kernelRelease string) bool {
+	matches := kernelMajorMinorRe.FindStringSubmatch(kernelRelease)
+	if len(matches) != 3 {
+		return false
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+
+	if major != ebpfMinKernelMajor {
+		return major > ebpfMinKernelMajor
+	}
+
+	return minor >= ebpfMinKernelMinor
+}
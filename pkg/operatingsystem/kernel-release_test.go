@@ -0,0 +1,30 @@
+package operatingsystem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+func TestIsEBPFCompatible(t *testing.T) {
+	var tests = []struct {
+		kernelRelease string
+		expected      bool
+	}{
+		{"3.18.0-1-generic", false},
+		{"4.4.0-210-generic", false},
+		{"4.13.16", false},
+		{"4.14.200-155.322.amzn2", true},
+		{"4.15.0-147-generic", true},
+		{"5.4.0-100-generic", true},
+		{"6.1.0-13-amd64", true},
+		{"not-a-kernel", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kernelRelease, func(t *testing.T) {
+			assert.Equal(t, tt.expected, operatingsystem.IsEBPFCompatible(tt.kernelRelease))
+		})
+	}
+}
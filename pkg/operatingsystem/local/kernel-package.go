@@ -0,0 +1,122 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// NewKernelPackage returns a new hydrated local implementation of operatingsystem.KernelPackage for the given
+// Manifest. It only requires the BusyBox image to be present, so it works without network access.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, manifest *Manifest) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: manifest.TargetID,
+		Name:            manifest.Name,
+		KernelRelease:   manifest.Uname.Release,
+		KernelVersion:   manifest.Uname.Version,
+		KernelMachine:   manifest.Uname.Machine,
+	}
+
+	if err := addOSRelease(kP, manifest); err != nil {
+		return nil, err
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, manifest); err != nil {
+		return nil, err
+	}
+
+	return kP, nil
+}
+
+// addOSRelease reads the manifest's `/etc/os-release`, which also provides the target ID unless the manifest
+// overrides it.
+func addOSRelease(kp *operatingsystem.KernelPackage, manifest *Manifest) error {
+	fileContents, err := ioutil.ReadFile(manifest.OSRelease)
+	if err != nil {
+		return fmt.Errorf("could not read os-release: %w", err)
+	}
+	kp.OSRelease = operatingsystem.FileContents(fileContents)
+
+	if kp.OperatingSystem == "" {
+		kp.OperatingSystem = operatingsystem.OSReleaseValue(kp.OSRelease, "ID")
+	}
+	if kp.OperatingSystem == "" {
+		return fmt.Errorf("could not find ID in %s and no target_id given", manifest.OSRelease)
+	}
+
+	return nil
+}
+
+// addSourcesAndConfiguration copies the manifest's headers into a staging volume and from there into the sources
+// volume at `/usr/src/kernels/<kernel release>`, where falco-driver-builder expects to find them, as well as the
+// conventional `/lib/modules/<kernel release>/build` symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, manifest *Manifest) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	stagingVol := dockerClient.MustCreateVolume()
+	defer dockerClient.MustRemoveVolumes(stagingVol)
+
+	headers, err := openHeaders(manifest.Headers)
+	if err != nil {
+		return fmt.Errorf("could not open headers: %w", err)
+	}
+	defer headers.Close()
+
+	if err := dockerClient.WriteTarToVolume(ctx, stagingVol, "/staging/", "/staging/", headers); err != nil {
+		return fmt.Errorf("could not copy headers: %w", err)
+	}
+
+	if manifest.Config != "" {
+		config, err := ioutil.ReadFile(manifest.Config)
+		if err != nil {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+
+		if err := dockerClient.WriteFileToVolume(kp.KernelConfiguration, "/lib/modules/", "/lib/modules/config", string(config)); err != nil {
+			return fmt.Errorf("could not copy config: %w", err)
+		}
+	}
+
+	script := `
+set -eu
+src=/staging
+if [ ! -f "${src}/Makefile" ]; then
+	src="$(find /staging -mindepth 2 -maxdepth 2 -name Makefile | head -n1 | xargs -r dirname)"
+fi
+if [ -z "${src}" ]; then
+	echo "could not find a kernel build tree in the headers" >&2
+	exit 1
+fi
+mkdir -p /usr/src/kernels /lib/modules/%[1]s
+cp -a "${src}" /usr/src/kernels/%[1]s
+ln -sfn /usr/src/kernels/%[1]s /lib/modules/%[1]s/build
+if [ -f /lib/modules/config ]; then
+	mv /lib/modules/config /lib/modules/%[1]s/config
+else
+	cp "${src}/.config" /lib/modules/%[1]s/config
+fi
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      docker.BusyBoxImage,
+			Entrypoint: []string{"/bin/sh"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.KernelRelease)},
+			Volumes: map[operatingsystem.Volume]string{
+				stagingVol:             "/staging/",
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
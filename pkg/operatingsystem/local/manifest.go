@@ -0,0 +1,113 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes a kernel whose headers are provided locally, e.g. a custom kernel built internally. Paths are
+// relative to the directory containing the manifest.
+//
+//	name: 5.15.0-custom
+//	headers: ./linux-headers-5.15.0-custom.tar.gz
+//	config: ./config-5.15.0-custom
+//	os_release: ./os-release
+//	target_id: ubuntu
+//	uname:
+//	  release: 5.15.0-custom
+//	  version: "#1 SMP Mon Jan 2 00:00:00 UTC 2023"
+//	  machine: x86_64
+type Manifest struct {
+	// Name is the kernel package name, defaulting to the kernel release.
+	Name string `yaml:"name"`
+	// Headers is the path of a directory, or tar archive optionally compressed with gzip, bzip2 or xz, containing the
+	// kernel build tree (i.e. what `/lib/modules/<kernel release>/build` links to on a host). A tar archive may
+	// contain the build tree in a single top-level directory.
+	Headers string `yaml:"headers"`
+	// Config is the path of the kernel configuration, defaulting to the `.config` in the build tree.
+	Config string `yaml:"config"`
+	// OSRelease is the path of the `/etc/os-release` of the hosts running the kernel.
+	OSRelease string `yaml:"os_release"`
+	// TargetID is the target ID falco-driver-loader resolves on the hosts running the kernel, defaulting to the `ID`
+	// in OSRelease.
+	TargetID string `yaml:"target_id"`
+	// Uname are the values of `uname` on the hosts running the kernel.
+	Uname struct {
+		// Release is the output of `uname -r`.
+		Release string `yaml:"release"`
+		// Version is the output of `uname -v`.
+		Version string `yaml:"version"`
+		// Machine is the output of `uname -m`.
+		Machine string `yaml:"machine"`
+	} `yaml:"uname"`
+}
+
+// LoadManifest reads and validates the manifest at the given path, resolving its paths relative to it.
+func LoadManifest(path string) (*Manifest, error) {
+	manifestBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("could not parse manifest %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	manifest.Headers = resolvePath(dir, manifest.Headers)
+	manifest.Config = resolvePath(dir, manifest.Config)
+	manifest.OSRelease = resolvePath(dir, manifest.OSRelease)
+
+	if manifest.Name == "" {
+		manifest.Name = manifest.Uname.Release
+	}
+	if manifest.Uname.Machine == "" {
+		manifest.Uname.Machine = "x86_64"
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) validate() error {
+	if m.Uname.Release == "" {
+		return fmt.Errorf("missing uname.release")
+	}
+	if m.Uname.Version == "" {
+		return fmt.Errorf("missing uname.version")
+	}
+
+	for field, path := range map[string]string{"headers": m.Headers, "os_release": m.OSRelease} {
+		if path == "" {
+			return fmt.Errorf("missing %s", field)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("could not find %s: %w", field, err)
+		}
+	}
+
+	if m.Config != "" {
+		if _, err := os.Stat(m.Config); err != nil {
+			return fmt.Errorf("could not find config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resolvePath returns the given path resolved relative to the given directory, unless it is absolute or empty.
+func resolvePath(dir string, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
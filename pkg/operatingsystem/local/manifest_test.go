@@ -0,0 +1,73 @@
+package local_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/local"
+)
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0o644))
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "headers", "Makefile"), "")
+	writeTestFile(t, filepath.Join(dir, "os-release"), "ID=ubuntu\n")
+	writeTestFile(t, filepath.Join(dir, "manifest.yaml"), `
+headers: ./headers
+os_release: os-release
+uname:
+  release: 5.15.0-custom
+  version: "#1 SMP Mon Jan 2 00:00:00 UTC 2023"
+`)
+
+	manifest, err := local.LoadManifest(filepath.Join(dir, "manifest.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "5.15.0-custom", manifest.Name)
+	assert.Equal(t, filepath.Join(dir, "headers"), manifest.Headers)
+	assert.Equal(t, "", manifest.Config)
+	assert.Equal(t, filepath.Join(dir, "os-release"), manifest.OSRelease)
+	assert.Equal(t, "", manifest.TargetID)
+	assert.Equal(t, "5.15.0-custom", manifest.Uname.Release)
+	assert.Equal(t, "#1 SMP Mon Jan 2 00:00:00 UTC 2023", manifest.Uname.Version)
+	assert.Equal(t, "x86_64", manifest.Uname.Machine)
+}
+
+func TestLoadManifestInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "os-release"), "ID=ubuntu\n")
+
+	var tests = []struct {
+		desc     string
+		manifest string
+	}{
+		{"missing uname", "headers: .\nos_release: os-release\n"},
+		{"missing headers", "headers: ./headers\nos_release: os-release\nuname: {release: a, version: '#1'}\n"},
+		{"missing os-release", "headers: .\nuname: {release: a, version: '#1'}\n"},
+		{"missing config", "headers: .\nconfig: config\nos_release: os-release\nuname: {release: a, version: '#1'}\n"},
+		{"not yaml", "headers: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			writeTestFile(t, filepath.Join(dir, "manifest.yaml"), tt.manifest)
+
+			_, err := local.LoadManifest(filepath.Join(dir, "manifest.yaml"))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package local
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system.
+const Name = "local"
+
+// ErrCannotListKernelPackages is returned when listing the kernel packages of the local operating system, as they are
+// only known by their manifests.
+var ErrCannotListKernelPackages = errors.New("local kernel packages cannot be listed, give the path of their manifest instead")
+
+// Local implements operatingsystem.OperatingSystem for kernels whose headers are provided locally, described by a
+// Manifest. It does not access the network so can be used on air-gapped machines.
+type Local struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewLocal returns a new local implementation of operatingsystem.OperatingSystem.
+func NewLocal(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Local{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for local.
+func (s *Local) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for local. Local kernel
+// packages cannot be listed so this always returns ErrCannotListKernelPackages.
+func (s *Local) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	return nil, ErrCannotListKernelPackages
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for local.
+// Note that the KernelPackageName in this context is the path of the kernel package's Manifest.
+func (s *Local) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	manifest, err := LoadManifest(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, manifest)
+}
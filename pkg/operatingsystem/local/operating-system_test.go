@@ -0,0 +1,71 @@
+package local_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/local"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	os := local.NewLocal(nil)
+
+	_, err := os.GetKernelPackageNames(context.Background())
+
+	assert.ErrorIs(t, err, local.ErrCannotListKernelPackages)
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	localOS := local.NewLocal(cli)
+
+	dir, err := ioutil.TempDir("", "local")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "linux-headers-5.15.0-custom", "Makefile"), "")
+	writeTestFile(t, filepath.Join(dir, "linux-headers-5.15.0-custom", "include", "generated", "autoconf.h"), "")
+	writeTestFile(t, filepath.Join(dir, "linux-headers-5.15.0-custom", ".config"), "CONFIG_BPF=y\n")
+	writeTestFile(t, filepath.Join(dir, "os-release"), "NAME=\"Ubuntu\"\nID=ubuntu\n")
+	writeTestFile(t, filepath.Join(dir, "manifest.yaml"), `
+headers: ./linux-headers-5.15.0-custom
+os_release: ./os-release
+uname:
+  release: 5.15.0-custom
+  version: "#7 SMP Mon Jan 2 00:00:00 UTC 2023"
+`)
+
+	res, err := localOS.GetKernelPackageByName(context.Background(), filepath.Join(dir, "manifest.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ubuntu", res.OperatingSystem)
+	assert.Equal(t, "5.15.0-custom", res.Name)
+	assert.Equal(t, "5.15.0-custom", res.KernelRelease)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=ubuntu")
+	assert.Equal(t, "falco_ubuntu_5.15.0-custom_7", res.ProbeName())
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/5.15.0-custom/include/generated/autoconf.h && " +
+				"ls /lib/modules/5.15.0-custom/build && " +
+				"grep CONFIG_BPF= /lib/modules/5.15.0-custom/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				res.KernelSources:       "/usr/src/",
+				res.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
@@ -0,0 +1,85 @@
+package mariner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const kernelMachine = "x86_64"
+
+// NewKernelPackage returns a new hydrated CBL-Mariner implementation of operatingsystem.KernelPackage for the given
+// release.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, release *Release, name string) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: release.OperatingSystemName(),
+		Name:            name,
+		KernelRelease:   name,
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, release, kP); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, release.Image)
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kP.KernelSources, kP.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelVersion = kernelVersion
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the kernel-devel package into the sources volume and links it to where
+// falco-driver-builder expects to find it (`/usr/src/kernels/<kernel release>`), as CBL-Mariner installs kernel
+// headers to `/usr/src/linux-headers-<kernel release>`, as well as the conventional
+// `/lib/modules/<kernel release>/build` symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, release *Release, kp *operatingsystem.KernelPackage) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	tdnfDownloaderImage, err := BuildTdnfDownloader(dockerClient, release)
+	if err != nil {
+		return fmt.Errorf("could not build tdnfdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+tdnf install -y --downloadonly --downloaddir="$(pwd)" kernel-devel-%[1]s > /dev/null
+rpm2cpio kernel-devel-%[1]s.*.rpm | cpio --extract --make-directories --quiet
+cp -a ./usr/src/. /usr/src/
+src="$(find /usr/src -mindepth 1 -maxdepth 1 -type d -name '*%[1]s*' | head -n1)"
+mkdir -p /usr/src/kernels /lib/modules/%[1]s
+ln -sfn "../$(basename "${src}")" /usr/src/kernels/%[1]s
+ln -sfn /usr/src/kernels/%[1]s /lib/modules/%[1]s/build
+cp "${src}/.config" /lib/modules/%[1]s/config
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      tdnfDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.KernelRelease)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package mariner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Mariner implements operatingsystem.OperatingSystem for a release of CBL-Mariner (Azure Linux).
+type Mariner struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	release      *Release
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for mariner.
+func (s *Mariner) GetName() string {
+	return s.release.OperatingSystemName()
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for mariner.
+// Note that KernelPackageNames in this context are the `<version>-<release>` of the kernel-devel package, which is
+// also the kernel release, e.g. `5.15.102.1-1.cm2`.
+func (s *Mariner) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	tdnfDownloaderImage, err := BuildTdnfDownloader(s.dockerClient, s.release)
+	if err != nil {
+		return nil, fmt.Errorf("could not build tdnfdownloader: %w", err)
+	}
+
+	out, err := s.dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      tdnfDownloaderImage,
+			Entrypoint: []string{"bash"},
+			Cmd:        []string{"-c", "tdnf list available kernel-devel | awk '{ print $2 }' | sort -uV"},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return operatingsystem.NewKernelPackageRefs(ParseKernelPackageNames(s.release, out), func(ref *operatingsystem.KernelPackageRef) {
+		ref.KernelRelease = ref.Name
+		ref.Architecture = kernelMachine
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for mariner.
+func (s *Mariner) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, s.release, name)
+}
+
+// ParseKernelPackageNames returns the eBPF compatible kernel package names of the given release from the given tdnf
+// output, ignoring any other lines tdnf may output (e.g. metadata refresh messages).
+func ParseKernelPackageNames(release *Release, out string) []string {
+	packageNames := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if !release.kernelPackageNameRe.MatchString(name) || !operatingsystem.IsEBPFCompatible(name) {
+			continue
+		}
+		packageNames = append(packageNames, name)
+	}
+
+	return packageNames
+}
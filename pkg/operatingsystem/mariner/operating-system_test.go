@@ -0,0 +1,95 @@
+package mariner_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/mariner"
+)
+
+func TestParseKernelPackageNames(t *testing.T) {
+	var tests = []struct {
+		release              *mariner.Release
+		out                  string
+		expectedPackageNames []string
+	}{
+		{
+			mariner.CBLMariner2,
+			`Refreshing metadata for: 'CBL-Mariner Official Base 2.0 x86_64'
+5.15.92.1-3.cm2
+5.15.102.1-1.cm2
+kernel-devel.x86_64
+1.0.1-1.cm1
+`,
+			[]string{"5.15.92.1-3.cm2", "5.15.102.1-1.cm2"},
+		},
+		{
+			mariner.AzureLinux3,
+			`Refreshing metadata for: 'Azure Linux Official Base 3.0 x86_64'
+6.6.44.1-1.azl3
+6.6.47.1-1.azl3
+5.15.102.1-1.cm2
+`,
+			[]string{"6.6.44.1-1.azl3", "6.6.47.1-1.azl3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.release.ID, func(t *testing.T) {
+			assert.Equal(t, tt.expectedPackageNames, mariner.ParseKernelPackageNames(tt.release, tt.out))
+		})
+	}
+}
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := mariner.CBLMariner2.NewMariner(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := mariner.CBLMariner2.NewMariner(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "5.15.102.1-1.cm2")
+	require.NoError(t, err)
+
+	assert.Equal(t, "mariner", res.OperatingSystem)
+	assert.Equal(t, "5.15.102.1-1.cm2", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=mariner")
+	assert.Equal(t, "falco_mariner_5.15.102.1-1.cm2_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
+
+func TestGetKernelPackageByNameAzureLinux3(t *testing.T) {
+	cli := docker.MustClient()
+	os := mariner.AzureLinux3.NewMariner(cli)
+
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	name := names[len(names)-1].Name
+
+	res, err := os.GetKernelPackageByName(context.Background(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "azurelinux", res.OperatingSystem)
+	assert.Regexp(t, regexp.MustCompile(`\.azl3$`), res.KernelRelease)
+	assert.Contains(t, res.OSRelease, "ID=azurelinux")
+	assert.Contains(t, res.ProbeName(), "falco_azurelinux_"+name+"_")
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
@@ -0,0 +1,50 @@
+package mariner
+
+import (
+	"regexp"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Release represents a major release of CBL-Mariner or of its successor Azure Linux. Each release has its own package
+// repository and `ID` in `/etc/os-release`, which falco-driver-loader uses as the target ID, so each release is its own
+// operating system.
+type Release struct {
+	// ID is the `ID` in this release's `/etc/os-release`, e.g. `mariner`.
+	ID string
+	// Image is the container image of this release, which its kernel packages are listed and downloaded from.
+	Image string
+
+	// kernelPackageNameRe matches the `<version>-<release>` of this release's kernel packages.
+	kernelPackageNameRe *regexp.Regexp
+}
+
+var (
+	// CBLMariner2 is CBL-Mariner 2.0, whose kernel packages are suffixed with `.cm2`, e.g. `5.15.102.1-1.cm2`.
+	CBLMariner2 = &Release{
+		ID:                  "mariner",
+		Image:               "mcr.microsoft.com/cbl-mariner/base/core:2.0",
+		kernelPackageNameRe: regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(\.[0-9]+)?-[0-9]+\.cm2$`),
+	}
+	// AzureLinux3 is Azure Linux 3.0, whose kernel packages are suffixed with `.azl3`, e.g. `6.6.47.1-1.azl3`.
+	AzureLinux3 = &Release{
+		ID:                  "azurelinux",
+		Image:               "mcr.microsoft.com/azurelinux/base/core:3.0",
+		kernelPackageNameRe: regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(\.[0-9]+)?-[0-9]+\.azl3$`),
+	}
+)
+
+// OperatingSystemName returns the name of the operating system for this release which is also the target ID that
+// falco-driver-loader resolves for it, e.g. `mariner`.
+func (r *Release) OperatingSystemName() string {
+	return r.ID
+}
+
+// NewMariner returns a new CBL-Mariner implementation of operatingsystem.OperatingSystem for this release.
+func (r *Release) NewMariner(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Mariner{
+		dockerClient: dockerClient,
+		release:      r,
+	}
+}
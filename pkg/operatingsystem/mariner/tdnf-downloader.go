@@ -0,0 +1,31 @@
+package mariner
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// TdnfDownloaderDockerfile represents the contents of a Dockerfile to build the tdnfdownloader image which downloads
+// and extracts RPM packages from the repositories of the release whose image it is formatted with.
+const TdnfDownloaderDockerfile = `FROM %s
+RUN tdnf install -y cpio findutils rpm \
+	&& tdnf clean all
+`
+
+// TdnfDownloaderRepository is the repository to build the tdnfdownloader image under.
+const TdnfDownloaderRepository = "docker.io/thoughtmachine/falco-tdnfdownloader"
+
+// BuildTdnfDownloader builds the tdnfdownloader docker image for the given release.
+func BuildTdnfDownloader(dockerClient *docker.Client, release *Release) (string, error) {
+	imageFQN := fmt.Sprintf("%s:%s", TdnfDownloaderRepository, release.ID)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: fmt.Sprintf(TdnfDownloaderDockerfile, release.Image),
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
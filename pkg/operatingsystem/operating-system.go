@@ -1,13 +1,25 @@
 package operatingsystem
 
+import "context"
+
 // OperatingSystem abstracts the implementation of determining which kernel packages are available and the retrieval of them for an Operating System.
 type OperatingSystem interface {
 	// GetName returns a unique string name for the implementation of this interface.
 	GetName() string
-	// GetKernelPackageNames returns a list of all available Kernel Package names.
-	GetKernelPackageNames() ([]string, error)
+	// GetKernelPackageNames returns references to all available Kernel Packages, i.e. their names along with anything
+	// known about them without hydrating them.
+	// Implementations should stop and return the context's error when the given context is cancelled.
+	GetKernelPackageNames(ctx context.Context) (KernelPackageRefs, error)
 	// GetKernelPackageByName returns a "hydrated" KernelPackage for the given Kernel Package name.
 	// "hydrated" means that the values are retrieved, so this function should perform the fetching of Kernel Sources, etc. for a KernelPackage
 	// and is the only place to return errors for those processes.
-	GetKernelPackageByName(name string) (*KernelPackage, error)
+	GetKernelPackageByName(ctx context.Context, name string) (*KernelPackage, error)
+}
+
+// CacheKeyer is optionally implemented by an OperatingSystem whose hydrated Kernel Packages depend on how it is
+// configured, e.g. where their Kernel Sources are fetched from, so that Kernel Packages hydrated with one
+// configuration are not reused from a cache for another.
+type CacheKeyer interface {
+	// CacheKey returns a key which differs between configurations which hydrate different Kernel Packages.
+	CacheKey() string
 }
@@ -0,0 +1,108 @@
+package photon
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Images are the container images of the supported Photon OS major releases, keyed by major release.
+var Images = map[string]string{
+	"4": "docker.io/library/photon:4.0",
+	"5": "docker.io/library/photon:5.0",
+}
+
+// Flavours are the kernel flavours Photon OS ships headers for, keyed by the suffix they add to the kernel release.
+// The generic flavour adds no suffix, whilst e.g. the ESX flavour which is optimised for VMware hypervisors adds
+// `-esx`.
+var Flavours = map[string]string{
+	"":     "linux-devel",
+	"-esx": "linux-esx-devel",
+}
+
+// develPackageRe matches a devel package in tdnf's list output, e.g.
+// `linux-esx-devel.x86_64  5.10.152-3.ph4  photon-updates`.
+var develPackageRe = regexp.MustCompile(`^(linux(-[a-z]+)?-devel)\.x86_64\s+([0-9]+\.[0-9]+\.[0-9]+-[0-9]+\.ph([0-9]+))(\s|$)`)
+
+// kernelReleaseRe matches the kernel release of a Photon OS kernel, e.g. `5.10.152-3.ph4-esx`.
+var kernelReleaseRe = regexp.MustCompile(`^([0-9]+\.[0-9]+\.[0-9]+-[0-9]+\.ph([0-9]+))(-[a-z]+)?$`)
+
+// DevelPackage represents a Photon OS kernel devel package.
+type DevelPackage struct {
+	// Name is the name of the package, e.g. `linux-esx-devel`.
+	Name string
+	// Version is the `<version>-<release>` of the package, e.g. `5.10.152-3.ph4`.
+	Version string
+	// MajorRelease is the Photon OS major release the package belongs to, e.g. `4`.
+	MajorRelease string
+	// Flavour is the suffix the package's kernel flavour adds to the kernel release, e.g. `-esx`.
+	Flavour string
+}
+
+// KernelRelease returns the kernel release of the kernel the package provides headers for, which is also its
+// KernelPackageName, e.g. `5.10.152-3.ph4-esx`.
+func (p *DevelPackage) KernelRelease() string {
+	return p.Version + p.Flavour
+}
+
+// ParseDevelPackages returns the devel packages of the supported flavours from the given tdnf list output, ignoring
+// any other lines tdnf may output (e.g. metadata refresh messages).
+func ParseDevelPackages(out string) []*DevelPackage {
+	develPackages := []*DevelPackage{}
+	for _, line := range strings.Split(out, "\n") {
+		matches := develPackageRe.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		develPackage := &DevelPackage{
+			Name:         matches[1],
+			Version:      matches[3],
+			MajorRelease: matches[4],
+			Flavour:      matches[2],
+		}
+		if Flavours[develPackage.Flavour] != develPackage.Name {
+			continue
+		}
+		develPackages = append(develPackages, develPackage)
+	}
+
+	return develPackages
+}
+
+// DevelPackageFromKernelRelease returns the devel package which provides the headers for the given kernel release,
+// e.g. `5.10.152-3.ph4-esx` -> `linux-esx-devel` `5.10.152-3.ph4`.
+func DevelPackageFromKernelRelease(kernelRelease string) (*DevelPackage, error) {
+	matches := kernelReleaseRe.FindStringSubmatch(kernelRelease)
+	if matches == nil {
+		return nil, fmt.Errorf("could not parse Photon OS kernel release %s", kernelRelease)
+	}
+
+	name, ok := Flavours[matches[3]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported Photon OS kernel flavour for %s: %s", kernelRelease, matches[3])
+	}
+
+	if _, ok := Images[matches[2]]; !ok {
+		return nil, fmt.Errorf("unsupported Photon OS major release for %s: %s", kernelRelease, matches[2])
+	}
+
+	return &DevelPackage{
+		Name:         name,
+		Version:      matches[1],
+		MajorRelease: matches[2],
+		Flavour:      matches[3],
+	}, nil
+}
+
+// majorReleases returns the supported Photon OS major releases in ascending order.
+func majorReleases() []string {
+	majors := []string{}
+	for major := range Images {
+		majors = append(majors, major)
+	}
+	sort.Strings(majors)
+
+	return majors
+}
@@ -0,0 +1,55 @@
+package photon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/photon"
+)
+
+func TestParseDevelPackages(t *testing.T) {
+	out := `Refreshing metadata for: 'VMware Photon Linux 4.0 (x86_64) Updates'
+linux-devel.x86_64                      5.10.152-3.ph4              photon-updates
+linux-esx-devel.x86_64                  5.10.152-3.ph4              photon-updates
+linux-rt-devel.x86_64                   5.10.152-3.ph4              photon-updates
+linux-devel.x86_64                      4.19.97-1.ph3               photon-updates
+linux-api-headers.noarch                5.10.152-1.ph4              photon-updates
+`
+
+	res := photon.ParseDevelPackages(out)
+
+	require.Len(t, res, 3)
+	assert.Equal(t, "5.10.152-3.ph4", res[0].KernelRelease())
+	assert.Equal(t, "5.10.152-3.ph4-esx", res[1].KernelRelease())
+	assert.Equal(t, "linux-esx-devel", res[1].Name)
+	assert.Equal(t, "4", res[1].MajorRelease)
+	assert.Equal(t, "4.19.97-1.ph3", res[2].KernelRelease())
+}
+
+func TestDevelPackageFromKernelRelease(t *testing.T) {
+	var tests = []struct {
+		kernelRelease string
+		expected      *photon.DevelPackage
+		expectedErr   bool
+	}{
+		{"5.10.152-3.ph4", &photon.DevelPackage{Name: "linux-devel", Version: "5.10.152-3.ph4", MajorRelease: "4", Flavour: ""}, false},
+		{"6.1.10-11.ph5-esx", &photon.DevelPackage{Name: "linux-esx-devel", Version: "6.1.10-11.ph5", MajorRelease: "5", Flavour: "-esx"}, false},
+		{"5.10.152-3.ph4-rt", nil, true},
+		{"4.19.97-1.ph3", nil, true},
+		{"5.15.102.1-1.cm2", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kernelRelease, func(t *testing.T) {
+			res, err := photon.DevelPackageFromKernelRelease(tt.kernelRelease)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+			assert.Equal(t, tt.kernelRelease, res.KernelRelease())
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package photon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const kernelMachine = "x86_64"
+
+// NewKernelPackage returns a new hydrated Photon OS implementation of operatingsystem.KernelPackage for the given
+// DevelPackage.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, develPackage *DevelPackage) (*operatingsystem.KernelPackage, error) {
+	tdnfDownloaderImage, err := BuildTdnfDownloader(dockerClient, develPackage.MajorRelease)
+	if err != nil {
+		return nil, fmt.Errorf("could not build tdnfdownloader: %w", err)
+	}
+
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            develPackage.KernelRelease(),
+		KernelRelease:   develPackage.KernelRelease(),
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, develPackage, tdnfDownloaderImage); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, Images[develPackage.MajorRelease])
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kP.KernelSources, kP.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelVersion = kernelVersion
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the devel package into the sources volume and links it to where
+// falco-driver-builder expects to find it (`/usr/src/kernels/<kernel release>`), as Photon OS installs kernel headers
+// to `/usr/src/linux-headers-<kernel release>`, as well as the conventional `/lib/modules/<kernel release>/build`
+// symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, develPackage *DevelPackage, tdnfDownloaderImage string) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+tdnf install -y --downloadonly --downloaddir="$(pwd)" %[1]s-%[2]s > /dev/null
+rpm2cpio %[1]s-%[2]s.*.rpm | cpio --extract --make-directories --quiet
+cp -a ./usr/src/. /usr/src/
+mkdir -p /usr/src/kernels /lib/modules/%[3]s
+ln -sfn ../linux-headers-%[3]s /usr/src/kernels/%[3]s
+ln -sfn /usr/src/kernels/%[3]s /lib/modules/%[3]s/build
+cp /usr/src/linux-headers-%[3]s/.config /lib/modules/%[3]s/config
+`
+
+	_, err := dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      tdnfDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, develPackage.Name, develPackage.Version, kp.KernelRelease)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
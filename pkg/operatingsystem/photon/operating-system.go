@@ -0,0 +1,81 @@
+package photon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system, which is also the `ID` in Photon OS's `/etc/os-release` that
+// falco-driver-loader uses as the target ID.
+const Name = "photon"
+
+// Photon implements operatingsystem.OperatingSystem for VMware Photon OS.
+type Photon struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewPhoton returns a new Photon OS implementation of operatingsystem.OperatingSystem.
+func NewPhoton(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Photon{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for photon.
+func (s *Photon) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for photon.
+// Note that KernelPackageNames in this context are the kernel releases of every flavour across all supported major
+// releases, e.g. `5.10.152-3.ph4` and `5.10.152-3.ph4-esx`, as the flavour is only distinguished by the package name.
+func (s *Photon) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	refs := operatingsystem.KernelPackageRefs{}
+	for _, major := range majorReleases() {
+		tdnfDownloaderImage, err := BuildTdnfDownloader(s.dockerClient, major)
+		if err != nil {
+			return nil, fmt.Errorf("could not build tdnfdownloader: %w", err)
+		}
+
+		out, err := s.dockerClient.Run(
+			ctx,
+			&docker.RunOpts{
+				Image:      tdnfDownloaderImage,
+				Entrypoint: []string{"bash"},
+				Cmd:        []string{"-c", "tdnf --quiet list available linux-devel linux-esx-devel"},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, develPackage := range ParseDevelPackages(out) {
+			if develPackage.MajorRelease != major || !operatingsystem.IsEBPFCompatible(develPackage.Version) {
+				continue
+			}
+			refs = append(refs, operatingsystem.KernelPackageRef{
+				Name:          develPackage.KernelRelease(),
+				KernelRelease: develPackage.KernelRelease(),
+				Architecture:  kernelMachine,
+				Repository:    Name + "-" + major,
+			})
+		}
+	}
+
+	return refs, nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for photon.
+func (s *Photon) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	develPackage, err := DevelPackageFromKernelRelease(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, develPackage)
+}
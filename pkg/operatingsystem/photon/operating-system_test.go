@@ -0,0 +1,49 @@
+package photon_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/photon"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := photon.NewPhoton(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	esx := 0
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-\d+\.ph\d+(-esx)?$`), name)
+		if strings.HasSuffix(name, "-esx") {
+			esx++
+		}
+	}
+	assert.NotZero(t, esx)
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := photon.NewPhoton(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "5.10.152-3.ph4-esx")
+	require.NoError(t, err)
+
+	assert.Equal(t, "photon", res.OperatingSystem)
+	assert.Equal(t, "5.10.152-3.ph4-esx", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=photon")
+	assert.Equal(t, "falco_photon_5.10.152-3.ph4-esx_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
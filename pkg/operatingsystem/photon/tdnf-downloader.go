@@ -0,0 +1,36 @@
+package photon
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// TdnfDownloaderDockerfileTemplate represents the template of the contents of a Dockerfile to build the tdnfdownloader
+// image which downloads and extracts RPM packages from a Photon OS release's repositories, given its image.
+const TdnfDownloaderDockerfileTemplate = `FROM %s
+RUN tdnf install -y cpio findutils rpm \
+	&& tdnf clean all
+`
+
+// TdnfDownloaderRepository is the repository to build the tdnfdownloader image under.
+const TdnfDownloaderRepository = "docker.io/thoughtmachine/falco-tdnfdownloader"
+
+// BuildTdnfDownloader builds the tdnfdownloader docker image for the given Photon OS major release.
+func BuildTdnfDownloader(dockerClient *docker.Client, majorRelease string) (string, error) {
+	image, ok := Images[majorRelease]
+	if !ok {
+		return "", fmt.Errorf("unsupported Photon OS major release: %s", majorRelease)
+	}
+
+	imageFQN := fmt.Sprintf("%s:photon-%s", TdnfDownloaderRepository, majorRelease)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: fmt.Sprintf(TdnfDownloaderDockerfileTemplate, image),
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -6,13 +6,94 @@ import (
 	"github.com/thought-machine/falco-probes/pkg/docker"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/amazonlinux2"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/amazonlinux2023"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/archive"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/bottlerocket"
 	"github.com/thought-machine/falco-probes/pkg/operatingsystem/cos"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/debian"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/declarative"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/fedoracoreos"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/flatcar"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/local"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/mariner"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/photon"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/rhel"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/suse"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/talos"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
 )
 
 // OperatingSystems represents the available operating systems to use and their constructors.
 var OperatingSystems = map[string]func(*docker.Client) operatingsystem.OperatingSystem{
-	amazonlinux2.Name: amazonlinux2.NewAmazonLinux2,
-	cos.Name:          cos.NewCos,
+	amazonlinux2.Name:        amazonlinux2.NewAmazonLinux2,
+	amazonlinux2.Aarch64Name: amazonlinux2.NewAmazonLinux2Aarch64,
+	amazonlinux2023.Name:     amazonlinux2023.NewAmazonLinux2023,
+	archive.Name:             archive.NewArchive,
+	bottlerocket.Name:        bottlerocket.NewBottlerocket,
+	cos.Name:                 cos.NewCos,
+	cos.Arm64Name:            cos.NewCosArm64,
+	debian.Name:              debian.NewDebian,
+	fedoracoreos.Name:        fedoracoreos.NewFedoraCoreOS,
+	local.Name:               local.NewLocal,
+	photon.Name:              photon.NewPhoton,
+	talos.Name:               talos.NewTalos,
+
+	ubuntu.Name:                          ubuntu.Generic.NewUbuntu,
+	ubuntu.Generic.OperatingSystemName(): ubuntu.Generic.NewUbuntu,
+	ubuntu.AWS.OperatingSystemName():     ubuntu.AWS.NewUbuntu,
+	ubuntu.GKE.OperatingSystemName():     ubuntu.GKE.NewUbuntu,
+	ubuntu.Azure.OperatingSystemName():   ubuntu.Azure.NewUbuntu,
+	ubuntu.GCP.OperatingSystemName():     ubuntu.GCP.NewUbuntu,
+
+	mariner.CBLMariner2.OperatingSystemName(): mariner.CBLMariner2.NewMariner,
+	mariner.AzureLinux3.OperatingSystemName(): mariner.AzureLinux3.NewMariner,
+
+	flatcar.Stable.OperatingSystemName(): flatcar.Stable.NewFlatcar,
+	flatcar.Beta.OperatingSystemName():   flatcar.Beta.NewFlatcar,
+	flatcar.Alpha.OperatingSystemName():  flatcar.Alpha.NewFlatcar,
+	flatcar.LTS.OperatingSystemName():    flatcar.LTS.NewFlatcar,
+
+	rhel.Rocky.OperatingSystemName():          rhel.Rocky.NewRHEL,
+	rhel.Alma.OperatingSystemName():           rhel.Alma.NewRHEL,
+	rhel.CentOSStream.OperatingSystemName():   rhel.CentOSStream.NewRHEL,
+	rhel.OracleLinuxUEK.OperatingSystemName(): rhel.OracleLinuxUEK.NewRHEL,
+
+	suse.SLES.OperatingSystemName():         suse.SLES.NewSUSE,
+	suse.OpenSUSELeap.OperatingSystemName(): suse.OpenSUSELeap.NewSUSE,
+}
+
+// Opts represents the options for resolving operating systems.
+type Opts struct {
+	Files []string `long:"file" description:"The path to a YAML file declaring additional operating systems, may be given multiple times" env:"OPERATING_SYSTEMS_FILES" env-delim:","`
+	Cos   cos.Opts `group:"cos" namespace:"cos"`
+}
+
+// Configure configures the operating systems with the given opts and loads the operating systems declared in the YAML
+// files given in opts.
+func Configure(opts *Opts) error {
+	cos.Configure(&opts.Cos)
+
+	return LoadDeclaredOperatingSystems(opts)
+}
+
+// LoadDeclaredOperatingSystems adds the operating systems declared in the YAML files given in opts to
+// OperatingSystems. See declarative.Definition for the format of the files.
+func LoadDeclaredOperatingSystems(opts *Opts) error {
+	for _, path := range opts.Files {
+		definitions, err := declarative.LoadDefinitions(path)
+		if err != nil {
+			return err
+		}
+
+		for _, definition := range definitions {
+			if _, ok := OperatingSystems[definition.Name]; ok {
+				return fmt.Errorf("operating system %s declared in %s is already defined", definition.Name, path)
+			}
+			OperatingSystems[definition.Name] = definition.NewOperatingSystem
+		}
+	}
+
+	return nil
 }
 
 // OperatingSystem resolves the given operatingsystem name to an implementation of operatingsystem.OperatingSystem
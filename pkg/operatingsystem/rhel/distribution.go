@@ -0,0 +1,114 @@
+package rhel
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Distribution represents a distribution rebuilt from, or upstream of, Red Hat Enterprise Linux. These share their
+// packaging of kernels, so they share their implementation, but falco-driver-loader resolves the target ID from each
+// distribution's `ID` in `/etc/os-release` so each distribution is its own operating system.
+type Distribution struct {
+	// ID is the `ID` in this distribution's `/etc/os-release`, e.g. `rocky`.
+	ID string
+	// Images are the container images of this distribution's supported major releases, keyed by major release.
+	Images map[string]string
+	// DevelPackage is the name of the package which holds the kernel's headers and configuration, e.g. `kernel-devel`.
+	DevelPackage string
+	// Repos are the dnf options which enable the repositories holding DevelPackage, if they are not enabled by
+	// default, e.g. `--enablerepo=ol*_UEKR*`.
+	Repos string
+	// PackageNameRe matches this distribution's kernel package names, capturing the major release each is built for.
+	PackageNameRe *regexp.Regexp
+}
+
+var (
+	// elMajorRe matches the major release in the release of an Enterprise Linux kernel package, e.g.
+	// `5.14.0-284.11.1.el9_2` -> 9, but not of an Unbreakable Enterprise Kernel package.
+	elMajorRe = regexp.MustCompile(`\.el([0-9]+)([._]|$)`)
+	// uekPackageNameRe matches the `<version>-<release>` of an Unbreakable Enterprise Kernel package, capturing the
+	// major release it is built for, e.g. `5.15.0-101.103.2.1.el8uek` -> 8.
+	uekPackageNameRe = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+-[0-9.]+\.el([0-9]+)uek$`)
+)
+
+var (
+	// Rocky is Rocky Linux.
+	Rocky = &Distribution{ID: "rocky", Images: map[string]string{
+		"8":  "docker.io/rockylinux/rockylinux:8",
+		"9":  "docker.io/rockylinux/rockylinux:9",
+		"10": "docker.io/rockylinux/rockylinux:10",
+	}, DevelPackage: "kernel-devel", PackageNameRe: elMajorRe}
+	// Alma is AlmaLinux.
+	Alma = &Distribution{ID: "almalinux", Images: map[string]string{
+		"8":  "docker.io/library/almalinux:8",
+		"9":  "docker.io/library/almalinux:9",
+		"10": "docker.io/library/almalinux:10",
+	}, DevelPackage: "kernel-devel", PackageNameRe: elMajorRe}
+	// CentOSStream is CentOS Stream.
+	CentOSStream = &Distribution{ID: "centos", Images: map[string]string{
+		"9":  "quay.io/centos/centos:stream9",
+		"10": "quay.io/centos/centos:stream10",
+	}, DevelPackage: "kernel-devel", PackageNameRe: elMajorRe}
+	// OracleLinuxUEK is Oracle Linux's Unbreakable Enterprise Kernels (UEK). Only the latest UEK release's repository
+	// is enabled by default so the repositories of every UEK release available to a major release are enabled.
+	OracleLinuxUEK = &Distribution{ID: "ol", Images: map[string]string{
+		"8": "docker.io/library/oraclelinux:8",
+		"9": "docker.io/library/oraclelinux:9",
+	}, DevelPackage: "kernel-uek-devel", Repos: "--enablerepo=ol*_UEKR*", PackageNameRe: uekPackageNameRe}
+)
+
+// OperatingSystemName returns the name of the operating system for this distribution which is also the target ID
+// that falco-driver-loader resolves for it, e.g. `rocky`.
+func (d *Distribution) OperatingSystemName() string {
+	return d.ID
+}
+
+// NewRHEL returns a new RHEL-family implementation of operatingsystem.OperatingSystem for this distribution.
+func (d *Distribution) NewRHEL(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &RHEL{
+		dockerClient: dockerClient,
+		distribution: d,
+	}
+}
+
+// majorReleases returns the major releases of this distribution in ascending order.
+func (d *Distribution) majorReleases() []string {
+	majors := []string{}
+	for major := range d.Images {
+		majors = append(majors, major)
+	}
+	sort.Slice(majors, func(i, j int) bool {
+		return len(majors[i]) < len(majors[j]) || (len(majors[i]) == len(majors[j]) && majors[i] < majors[j])
+	})
+
+	return majors
+}
+
+// ImageForKernelPackage returns the container image of the major release which the given kernel package name is built
+// for, e.g. `5.14.0-284.11.1.el9_2` -> the image of major release 9.
+func (d *Distribution) ImageForKernelPackage(name string) (string, error) {
+	matches := d.PackageNameRe.FindStringSubmatch(name)
+	if matches == nil {
+		return "", fmt.Errorf("could not find the major release of %s", name)
+	}
+
+	image, ok := d.Images[matches[1]]
+	if !ok {
+		return "", fmt.Errorf("unsupported major release of %s for %s: %s", d.ID, name, matches[1])
+	}
+
+	return image, nil
+}
+
+// dnf returns the dnf command, with this distribution's repositories enabled, to run the given subcommand.
+func (d *Distribution) dnf(subcommand string) string {
+	if d.Repos == "" {
+		return "dnf --quiet " + subcommand
+	}
+
+	return "dnf --quiet " + d.Repos + " " + subcommand
+}
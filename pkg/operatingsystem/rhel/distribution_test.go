@@ -0,0 +1,39 @@
+package rhel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/rhel"
+)
+
+func TestImageForKernelPackage(t *testing.T) {
+	var tests = []struct {
+		distribution  *rhel.Distribution
+		name          string
+		expectedImage string
+		expectedErr   bool
+	}{
+		{rhel.Rocky, "4.18.0-477.10.1.el8_8", "docker.io/rockylinux/rockylinux:8", false},
+		{rhel.Rocky, "5.14.0-284.11.1.el9_2", "docker.io/rockylinux/rockylinux:9", false},
+		{rhel.Alma, "6.12.0-55.9.1.el10_0", "docker.io/library/almalinux:10", false},
+		{rhel.CentOSStream, "5.14.0-325.el9", "quay.io/centos/centos:stream9", false},
+		{rhel.CentOSStream, "4.18.0-490.el8", "", true},
+		{rhel.Rocky, "6.1.25-37.47.amzn2023", "", true},
+		{rhel.OracleLinuxUEK, "5.15.0-101.103.2.1.el8uek", "docker.io/library/oraclelinux:8", false},
+		{rhel.OracleLinuxUEK, "4.14.35-2047.524.5.el7uek", "", true},
+		{rhel.OracleLinuxUEK, "4.18.0-477.10.1.el8_8", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.distribution.ID+"/"+tt.name, func(t *testing.T) {
+			image, err := tt.distribution.ImageForKernelPackage(tt.name)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedImage, image)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package rhel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// DnfDownloaderDockerfileTemplate represents the template of the contents of a Dockerfile to build the dnfdownloader
+// image which downloads RPM packages from a distribution's repositories, given the distribution's image.
+const DnfDownloaderDockerfileTemplate = `FROM %s
+RUN dnf install -y cpio dnf-plugins-core findutils \
+	&& dnf clean all
+`
+
+// DnfDownloaderRepository is the repository to build the dnfdownloader image under.
+const DnfDownloaderRepository = "docker.io/thoughtmachine/falco-dnfdownloader"
+
+// BuildDnfDownloader builds the dnfdownloader docker image for the given distribution image.
+func BuildDnfDownloader(dockerClient *docker.Client, image string) (string, error) {
+	imageFQN := fmt.Sprintf("%s:%s", DnfDownloaderRepository, dnfDownloaderTag(image))
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: fmt.Sprintf(DnfDownloaderDockerfileTemplate, image),
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
+
+// dnfDownloaderTag returns the tag of the dnfdownloader image for the given distribution image, e.g.
+// `docker.io/rockylinux/rockylinux:9` -> `rockylinux-9`.
+func dnfDownloaderTag(image string) string {
+	name := image[strings.LastIndex(image, "/")+1:]
+
+	return strings.ReplaceAll(name, ":", "-")
+}
@@ -0,0 +1,105 @@
+package rhel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// NewKernelPackage returns a new hydrated RHEL-family implementation of operatingsystem.KernelPackage for the given
+// distribution.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, distribution *Distribution, name string) (*operatingsystem.KernelPackage, error) {
+	image, err := distribution.ImageForKernelPackage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dnfDownloaderImage, err := BuildDnfDownloader(dockerClient, image)
+	if err != nil {
+		return nil, fmt.Errorf("could not build dnfdownloader: %w", err)
+	}
+
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: distribution.OperatingSystemName(),
+		Name:            name,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, distribution, dnfDownloaderImage); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	if err := addKernelReleaseAndVersionAndMachine(ctx, dockerClient, kP); err != nil {
+		return nil, err
+	}
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the distribution's DevelPackage into the sources volume, as it would be installed
+// on a host, as well as the conventional `/lib/modules/<kernel release>/build` symlink and configuration in the
+// configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, distribution *Distribution, dnfDownloaderImage string) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+%[3]s %[2]s-%[1]s
+rpm2cpio %[2]s-%[1]s.$(uname -m).rpm | cpio --extract --make-directories --quiet
+cp -a ./usr/src/. /usr/src/
+release="%[1]s.$(uname -m)"
+mkdir -p "/lib/modules/${release}"
+ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+cp "/usr/src/kernels/${release}/.config" "/lib/modules/${release}/config"
+`
+
+	_, err := dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      dnfDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.Name, distribution.DevelPackage, distribution.dnf("download"))},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addKernelReleaseAndVersionAndMachine reads the kernel release, version and machine from the extracted DevelPackage.
+func addKernelReleaseAndVersionAndMachine(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage) error {
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kp.KernelSources)
+	if err != nil {
+		return err
+	}
+	kp.KernelRelease = kernelRelease
+
+	kernelVersion, err := dockerClient.GetGeneratedDefine(ctx, kp.KernelSources, kp.KernelRelease, "UTS_VERSION")
+	if err != nil {
+		return err
+	}
+	kp.KernelVersion = kernelVersion
+
+	kernelMachine, err := dockerClient.GetGeneratedDefine(ctx, kp.KernelSources, kp.KernelRelease, "UTS_MACHINE")
+	if err != nil {
+		return err
+	}
+	kp.KernelMachine = kernelMachine
+
+	return nil
+}
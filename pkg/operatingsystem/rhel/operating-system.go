@@ -0,0 +1,77 @@
+package rhel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// RHEL implements operatingsystem.OperatingSystem for a RHEL-family distribution.
+type RHEL struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	distribution *Distribution
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for a RHEL-family distribution.
+func (s *RHEL) GetName() string {
+	return s.distribution.OperatingSystemName()
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for a RHEL-family
+// distribution. Note that KernelPackageNames in this context are the `<version>-<release>` of the distribution's
+// DevelPackage across all supported major releases, e.g. `5.14.0-284.11.1.el9_2` or `5.15.0-101.103.2.1.el8uek`.
+func (s *RHEL) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	refs := operatingsystem.KernelPackageRefs{}
+	for _, major := range s.distribution.majorReleases() {
+		dnfDownloaderImage, err := BuildDnfDownloader(s.dockerClient, s.distribution.Images[major])
+		if err != nil {
+			return nil, fmt.Errorf("could not build dnfdownloader: %w", err)
+		}
+
+		out, err := s.dockerClient.Run(
+			ctx,
+			&docker.RunOpts{
+				Image:      dnfDownloaderImage,
+				Entrypoint: []string{"bash"},
+				Cmd:        []string{"-c", s.distribution.dnf("repoquery --queryformat '%{version}-%{release}' "+s.distribution.DevelPackage) + " | sort -uV"},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, operatingsystem.NewKernelPackageRefs(s.distribution.ParseKernelPackageNames(out), func(ref *operatingsystem.KernelPackageRef) {
+			ref.KernelRelease = ref.Name + ".x86_64"
+			ref.Architecture = "x86_64"
+			ref.Repository = s.distribution.ID + "-" + major
+		})...)
+	}
+
+	return refs, nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for a RHEL-family
+// distribution.
+func (s *RHEL) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, s.distribution, name)
+}
+
+// ParseKernelPackageNames returns the eBPF compatible kernel package names of this distribution's supported major
+// releases from the given dnf output, ignoring any other lines dnf may output (e.g. metadata refresh messages).
+func (d *Distribution) ParseKernelPackageNames(out string) []string {
+	packageNames := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if _, err := d.ImageForKernelPackage(name); err != nil || !operatingsystem.IsEBPFCompatible(name) {
+			continue
+		}
+		packageNames = append(packageNames, name)
+	}
+
+	return packageNames
+}
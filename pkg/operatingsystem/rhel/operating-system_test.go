@@ -0,0 +1,102 @@
+package rhel_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/rhel"
+)
+
+func TestParseKernelPackageNames(t *testing.T) {
+	out := `Last metadata expiration check: 0:00:01 ago on Mon 17 Apr 2023 10:00:00 UTC.
+4.14.35-2047.524.5.el7uek
+4.18.0-477.10.1.el8_8
+5.4.17-2136.318.7.1.el8uek
+5.15.0-101.103.2.1.el8uek
+5.15.0-101.103.2.1.el9uek
+`
+
+	assert.Equal(t, []string{
+		"5.4.17-2136.318.7.1.el8uek",
+		"5.15.0-101.103.2.1.el8uek",
+		"5.15.0-101.103.2.1.el9uek",
+	}, rhel.OracleLinuxUEK.ParseKernelPackageNames(out))
+	assert.Equal(t, []string{
+		"4.18.0-477.10.1.el8_8",
+	}, rhel.Rocky.ParseKernelPackageNames(out))
+}
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+
+	var tests = []struct {
+		distribution *rhel.Distribution
+		nameRe       *regexp.Regexp
+	}{
+		{rhel.Rocky, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.el\d+(_\d+)?$`)},
+		{rhel.Alma, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.el\d+(_\d+)?$`)},
+		{rhel.CentOSStream, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.el\d+(_\d+)?$`)},
+		{rhel.OracleLinuxUEK, regexp.MustCompile(`^\d+\.\d+\.\d+-[\d.]+\.el\duek$`)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.distribution.ID, func(t *testing.T) {
+			os := tt.distribution.NewRHEL(cli)
+
+			res, err := os.GetKernelPackageNames(context.Background())
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, res)
+			for _, name := range res.Names() {
+				assert.Regexp(t, tt.nameRe, name)
+			}
+		})
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := rhel.Rocky.NewRHEL(cli)
+
+	// Rocky Linux only keeps the kernels of the latest minor releases so we cannot hardcode a kernel package name.
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	name := names[len(names)-1].Name
+
+	res, err := os.GetKernelPackageByName(context.Background(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "rocky", res.OperatingSystem)
+	assert.Equal(t, name+".x86_64", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#1 SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=\"rocky\"")
+	assert.Equal(t, "falco_rocky_"+name+".x86_64_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
+
+func TestGetKernelPackageByNameOracleLinuxUEK(t *testing.T) {
+	cli := docker.MustClient()
+	os := rhel.OracleLinuxUEK.NewRHEL(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "5.15.0-101.103.2.1.el8uek")
+	require.NoError(t, err)
+
+	assert.Equal(t, "ol", res.OperatingSystem)
+	assert.Equal(t, "5.15.0-101.103.2.1.el8uek.x86_64", res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#\d+ SMP`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=\"ol\"")
+	assert.Equal(t, "falco_ol_5.15.0-101.103.2.1.el8uek.x86_64_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
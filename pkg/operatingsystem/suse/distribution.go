@@ -0,0 +1,85 @@
+package suse
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// LeapImages are the container images of the supported openSUSE Leap releases, keyed by release. Since Leap 15.3,
+// Leap ships the kernels of the matching SUSE Linux Enterprise service pack, so the kernels of both distributions are
+// listed from Leap's public repositories rather than SLES's, which require registration.
+var LeapImages = map[string]string{
+	"15.5": "docker.io/opensuse/leap:15.5",
+	"15.6": "docker.io/opensuse/leap:15.6",
+}
+
+// Distribution represents a distribution which ships SUSE Linux Enterprise kernels. falco-driver-loader resolves the
+// target ID from each distribution's `ID` in `/etc/os-release` so each distribution is its own operating system.
+type Distribution struct {
+	// ID is the `ID` in this distribution's `/etc/os-release`, e.g. `sles`.
+	ID string
+	// OSReleaseImages are the container images to read this distribution's `/etc/os-release` from, keyed by the
+	// matching Leap release.
+	OSReleaseImages map[string]string
+}
+
+var (
+	// SLES is SUSE Linux Enterprise Server.
+	SLES = &Distribution{ID: "sles", OSReleaseImages: map[string]string{
+		"15.5": "registry.suse.com/suse/sle15:15.5",
+		"15.6": "registry.suse.com/suse/sle15:15.6",
+	}}
+	// OpenSUSELeap is openSUSE Leap.
+	OpenSUSELeap = &Distribution{ID: "opensuse-leap", OSReleaseImages: LeapImages}
+)
+
+// kernelReleaseRe matches the SUSE Linux Enterprise service pack in the `<version>-<release>` of a kernel package,
+// e.g. `5.14.21-150500.55.7.1` -> 15, 05.
+var kernelReleaseRe = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+-(1[0-9])([0-9]{2})00\.`)
+
+// OperatingSystemName returns the name of the operating system for this distribution which is also the target ID
+// that falco-driver-loader resolves for it, e.g. `sles`.
+func (d *Distribution) OperatingSystemName() string {
+	return d.ID
+}
+
+// NewSUSE returns a new SUSE implementation of operatingsystem.OperatingSystem for this distribution.
+func (d *Distribution) NewSUSE(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &SUSE{
+		dockerClient: dockerClient,
+		distribution: d,
+	}
+}
+
+// LeapRelease returns the openSUSE Leap release which ships the given kernel package, e.g.
+// `5.14.21-150500.55.7.1` -> `15.5`.
+func LeapRelease(name string) (string, error) {
+	matches := kernelReleaseRe.FindStringSubmatch(name)
+	if matches == nil {
+		return "", fmt.Errorf("could not find the service pack of %s", name)
+	}
+
+	servicePack, _ := strconv.Atoi(matches[2])
+	leapRelease := fmt.Sprintf("%s.%d", matches[1], servicePack)
+	if _, ok := LeapImages[leapRelease]; !ok {
+		return "", fmt.Errorf("unsupported openSUSE Leap release for %s: %s", name, leapRelease)
+	}
+
+	return leapRelease, nil
+}
+
+// leapReleases returns the supported openSUSE Leap releases in ascending order.
+func leapReleases() []string {
+	releases := []string{}
+	for release := range LeapImages {
+		releases = append(releases, release)
+	}
+	sort.Strings(releases)
+
+	return releases
+}
@@ -0,0 +1,101 @@
+package suse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const (
+	kernelMachine = "x86_64"
+	// kernelVersion is the `uname -v` of SUSE kernels. SUSE kernels are only built once per package release so their
+	// build number is always 1, and falco-driver-loader reads nothing else from it.
+	kernelVersion = "#1 SMP"
+)
+
+// NewKernelPackage returns a new hydrated SUSE implementation of operatingsystem.KernelPackage for the given
+// distribution.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, distribution *Distribution, name string) (*operatingsystem.KernelPackage, error) {
+	leapRelease, err := LeapRelease(name)
+	if err != nil {
+		return nil, err
+	}
+
+	zypperDownloaderImage, err := BuildZypperDownloader(dockerClient, leapRelease)
+	if err != nil {
+		return nil, fmt.Errorf("could not build zypperdownloader: %w", err)
+	}
+
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: distribution.OperatingSystemName(),
+		Name:            name,
+		KernelVersion:   kernelVersion,
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, zypperDownloaderImage); err != nil {
+		return nil, err
+	}
+
+	osRelease, err := dockerClient.GetOSRelease(ctx, distribution.OSReleaseImages[leapRelease])
+	if err != nil {
+		return nil, err
+	}
+	kP.OSRelease = osRelease
+
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kP.KernelSources)
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelRelease = kernelRelease
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the kernel-devel and kernel-default-devel packages into the sources volume. The
+// kernel-default-devel package contains the build tree (`/usr/src/linux-<version>-obj/<arch>/default`) which is
+// linked to where falco-driver-builder expects to find it (`/usr/src/kernels/<kernel release>`), as well as the
+// conventional `/lib/modules/<kernel release>/build` symlink and configuration in the configuration volume. The build
+// tree refers to the kernel-devel sources (`/usr/src/linux-<version>`) by absolute paths, so these are rewritten to be
+// relative to the sources volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, zypperDownloaderImage string) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	script := `
+set -euo pipefail
+zypper --non-interactive --quiet download kernel-devel=%[1]s kernel-default-devel=%[1]s
+cd /
+find /var/cache/zypp/packages -name '*.rpm' | while read -r rpm; do rpm2cpio "${rpm}" | cpio --extract --make-directories --quiet; done
+objdir="$(echo /usr/src/linux-*-obj/$(uname -m)/default)"
+release="$(grep -o 'UTS_RELEASE ".*"' "${objdir}/include/generated/utsrelease.h" | cut -d'"' -f2)"
+for link in $(find "${objdir}" -maxdepth 1 -type l -lname '/usr/src/*'); do
+	ln -sfnr "$(readlink "${link}")" "${link}"
+done
+sed -i 's#/usr/src/#$(abspath $(dir $(realpath $(lastword $(MAKEFILE_LIST))))/../../..)/#g' "${objdir}/Makefile"
+mkdir -p /usr/src/kernels "/lib/modules/${release}"
+ln -sfnr "${objdir}" "/usr/src/kernels/${release}"
+ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+cp "${objdir}/.config" "/lib/modules/${release}/config"
+`
+
+	_, err := dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      zypperDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.Name)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package suse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// listKernelPackagesScript prints the `<version>-<release>` of every available kernel-default-devel package from
+// zypper's `| `-separated search table.
+const listKernelPackagesScript = `zypper --non-interactive --quiet search --details --match-exact kernel-default-devel | ` +
+	`awk -F'|' '{ gsub(/ /, "", $4); print $4 }' | sort -uV`
+
+// kernelPackageNameRe matches the `<version>-<release>` of SUSE Linux Enterprise kernels, e.g. `5.14.21-150500.55.7.1`.
+var kernelPackageNameRe = regexp.MustCompile(`^\d+\.\d+\.\d+-1\d{5}(\.\d+)+$`)
+
+// SUSE implements operatingsystem.OperatingSystem for a distribution which ships SUSE Linux Enterprise kernels.
+type SUSE struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	distribution *Distribution
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for a SUSE distribution.
+func (s *SUSE) GetName() string {
+	return s.distribution.OperatingSystemName()
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for a SUSE distribution.
+// Note that KernelPackageNames in this context are the `<version>-<release>` of the kernel-default-devel package
+// across all supported service packs, e.g. `5.14.21-150500.55.7.1`.
+func (s *SUSE) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	refs := operatingsystem.KernelPackageRefs{}
+	for _, leapRelease := range leapReleases() {
+		zypperDownloaderImage, err := BuildZypperDownloader(s.dockerClient, leapRelease)
+		if err != nil {
+			return nil, fmt.Errorf("could not build zypperdownloader: %w", err)
+		}
+
+		out, err := s.dockerClient.Run(
+			ctx,
+			&docker.RunOpts{
+				Image:      zypperDownloaderImage,
+				Entrypoint: []string{"bash"},
+				Cmd:        []string{"-c", listKernelPackagesScript},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, operatingsystem.NewKernelPackageRefs(ParseKernelPackageNames(out), func(ref *operatingsystem.KernelPackageRef) {
+			ref.Architecture = kernelMachine
+			ref.Repository = "leap-" + leapRelease
+		})...)
+	}
+
+	return refs, nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for a SUSE distribution.
+func (s *SUSE) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	return NewKernelPackage(ctx, s.dockerClient, s.distribution, name)
+}
+
+// ParseKernelPackageNames returns the eBPF compatible SUSE Linux Enterprise kernel package names of the supported
+// service packs from the given zypper output, ignoring any other lines zypper may output (e.g. the table header).
+func ParseKernelPackageNames(out string) []string {
+	packageNames := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if !kernelPackageNameRe.MatchString(name) || !operatingsystem.IsEBPFCompatible(name) {
+			continue
+		}
+		if _, err := LeapRelease(name); err != nil {
+			continue
+		}
+		packageNames = append(packageNames, name)
+	}
+
+	return packageNames
+}
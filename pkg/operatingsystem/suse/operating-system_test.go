@@ -0,0 +1,64 @@
+package suse_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/suse"
+)
+
+func TestParseKernelPackageNames(t *testing.T) {
+	out := `Loading repository data...
+Reading installed packages...
+S | Name                 | Type    | Version               | Arch   | Repository
+--+----------------------+---------+-----------------------+--------+-------------------
+v | kernel-default-devel | package | 5.14.21-150500.55.7.1 | x86_64 | Update repository
+v | kernel-default-devel | package | 5.14.21-150500.53.2   | x86_64 | Main Repository
+5.3.18-150300.59.93.1
+5.14.21-150500.53.2
+5.14.21-150500.55.7.1
+`
+
+	assert.Equal(t, []string{"5.14.21-150500.53.2", "5.14.21-150500.55.7.1"}, suse.ParseKernelPackageNames(out))
+}
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+
+	for _, distribution := range []*suse.Distribution{suse.SLES, suse.OpenSUSELeap} {
+		distribution := distribution
+		t.Run(distribution.ID, func(t *testing.T) {
+			os := distribution.NewSUSE(cli)
+
+			res, err := os.GetKernelPackageNames(context.Background())
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, res)
+			for _, name := range res.Names() {
+				assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-1\d{5}(\.\d+)+$`), name)
+			}
+		})
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := suse.SLES.NewSUSE(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "5.14.21-150500.55.7.1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "sles", res.OperatingSystem)
+	assert.Equal(t, "5.14.21-150500.55.7-default", res.KernelRelease)
+	assert.Equal(t, "#1 SMP", res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=\"sles\"")
+	assert.Equal(t, "falco_sles_5.14.21-150500.55.7-default_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
@@ -0,0 +1,37 @@
+package suse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// ZypperDownloaderDockerfileTemplate represents the template of the contents of a Dockerfile to build the
+// zypperdownloader image which downloads RPM packages from an openSUSE Leap release's repositories, given its image.
+const ZypperDownloaderDockerfileTemplate = `FROM %s
+RUN zypper --non-interactive install cpio findutils gawk \
+	&& zypper clean --all
+`
+
+// ZypperDownloaderRepository is the repository to build the zypperdownloader image under.
+const ZypperDownloaderRepository = "docker.io/thoughtmachine/falco-zypperdownloader"
+
+// BuildZypperDownloader builds the zypperdownloader docker image for the given openSUSE Leap release.
+func BuildZypperDownloader(dockerClient *docker.Client, leapRelease string) (string, error) {
+	image, ok := LeapImages[leapRelease]
+	if !ok {
+		return "", fmt.Errorf("unsupported openSUSE Leap release: %s", leapRelease)
+	}
+
+	imageFQN := fmt.Sprintf("%s:leap-%s", ZypperDownloaderRepository, strings.ReplaceAll(leapRelease, ".", "-"))
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: fmt.Sprintf(ZypperDownloaderDockerfileTemplate, image),
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
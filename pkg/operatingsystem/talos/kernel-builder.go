@@ -0,0 +1,32 @@
+package talos
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// KernelBuilderDockerfile represents the contents of a Dockerfile to build the kernelbuilder image which downloads and
+// prepares upstream kernel sources with a given configuration.
+const KernelBuilderDockerfile = `FROM ubuntu:24.04
+RUN apt-get update \
+	&& apt-get install -y bc bison build-essential ca-certificates curl flex libelf-dev libssl-dev xz-utils \
+	&& rm -rf /var/lib/apt/lists/*
+`
+
+// KernelBuilderRepository is the repository to build the kernelbuilder image under.
+const KernelBuilderRepository = "docker.io/thoughtmachine/falco-kernelbuilder"
+
+// BuildKernelBuilder builds the kernelbuilder docker image.
+func BuildKernelBuilder(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:latest", KernelBuilderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: KernelBuilderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
@@ -0,0 +1,101 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const (
+	kernelMachine = "x86_64"
+	// kernelVersion is the `uname -v` of Talos kernels. Talos kernels are only built once per release so their build
+	// number is always 1, and falco-driver-loader reads nothing else from it.
+	kernelVersion = "#1 SMP"
+)
+
+// NewKernelPackage returns a new hydrated Talos implementation of operatingsystem.KernelPackage for the given Release.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, release *Release) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: Name,
+		Name:            release.Name(),
+		KernelVersion:   kernelVersion,
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, release); err != nil {
+		return nil, err
+	}
+
+	addOSRelease(kP, release)
+
+	kernelRelease, err := dockerClient.GetKernelRelease(ctx, kP.KernelSources)
+	if err != nil {
+		return nil, err
+	}
+	kP.KernelRelease = kernelRelease
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration downloads the release's upstream kernel sources, prepares them with the release's kernel
+// configuration and copies them into the sources volume at `/usr/src/kernels/<kernel release>`, where
+// falco-driver-builder expects to find them, as well as the conventional `/lib/modules/<kernel release>/build` symlink
+// and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, release *Release) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	kernelBuilderImage, err := BuildKernelBuilder(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build kernelbuilder: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+curl -sSfL -o linux.tar.xz %[1]s
+echo "%[2]s  linux.tar.xz" | sha256sum --check --quiet
+curl -sSfL -o config %[3]s
+tar -xf linux.tar.xz
+src="$(pwd)/linux-%[4]s"
+cp config "${src}/.config"
+make -s -C "${src}" olddefconfig modules_prepare
+release="$(make -s -C "${src}" kernelrelease)"
+mkdir -p /usr/src/kernels "/lib/modules/${release}"
+cp -a "${src}" "/usr/src/kernels/${release}"
+ln -sfn "/usr/src/kernels/${release}" "/lib/modules/${release}/build"
+cp config "/lib/modules/${release}/config"
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      kernelBuilderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd: []string{"-c", fmt.Sprintf(script,
+				release.Kernel.SourcesURL, release.Kernel.SHA256, release.Kernel.ConfigURL, release.Kernel.Version)},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addOSRelease mocks the `/etc/os-release` of a Talos host as Talos do not publish a container image of their
+// operating system. falco-driver-loader only reads the `ID` from it.
+func addOSRelease(kp *operatingsystem.KernelPackage, release *Release) {
+	kp.OSRelease = operatingsystem.FileContents(fmt.Sprintf(`NAME="Talos"
+ID=talos
+VERSION_ID=%[1]s
+PRETTY_NAME="Talos (%[1]s)"
+HOME_URL="https://www.talos.dev/"
+`, release.Version))
+}
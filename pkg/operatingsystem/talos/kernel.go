@@ -0,0 +1,112 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// TalosSourceURL is where the sources of Talos are served from at `<ref>/<path>`.
+	TalosSourceURL = "https://raw.githubusercontent.com/siderolabs/talos/"
+	// PkgsSourceURL is where the sources of siderolabs/pkgs, which builds the Talos kernel, are served from at
+	// `<ref>/<path>`.
+	PkgsSourceURL = "https://raw.githubusercontent.com/siderolabs/pkgs/"
+	// KernelOrgURL is where the upstream kernel sources are served from.
+	KernelOrgURL = "https://cdn.kernel.org/pub/linux/kernel/"
+
+	// kernelConfigPath is the path of the kernel configuration in siderolabs/pkgs.
+	kernelConfigPath = "kernel/build/config-amd64"
+)
+
+var (
+	// pkgsRe matches the siderolabs/pkgs ref a Talos release is built from in its Makefile, e.g.
+	// `PKGS ?= v1.6.0-7-g0ea7e9b` -> `v1.6.0-7-g0ea7e9b`.
+	pkgsRe = regexp.MustCompile(`(?m)^PKGS \?= (\S+)$`)
+	// pkgsDescribeRe matches the commit of a `git describe` siderolabs/pkgs ref, e.g. `v1.6.0-7-g0ea7e9b` -> `0ea7e9b`.
+	pkgsDescribeRe = regexp.MustCompile(`-g([0-9a-f]+)$`)
+	// linuxVersionRe and linuxSHA256Re match the upstream kernel siderolabs/pkgs builds in its Pkgfile, e.g.
+	// `linux_version: 6.6.8`.
+	linuxVersionRe = regexp.MustCompile(`(?m)^\s*linux_version: (\S+)$`)
+	linuxSHA256Re  = regexp.MustCompile(`(?m)^\s*linux_sha256: ([0-9a-f]{64})$`)
+)
+
+// Kernel represents the kernel shipped in a Talos release, which is an upstream kernel built with the configuration
+// from siderolabs/pkgs.
+type Kernel struct {
+	// Version is the upstream version of the kernel, e.g. `6.6.8`.
+	Version string
+	// SHA256 is the expected SHA-256 hash of the upstream kernel sources archive.
+	SHA256 string
+	// SourcesURL is the URL to download the upstream kernel sources archive from.
+	SourcesURL string
+	// ConfigURL is the URL to download the kernel's configuration from.
+	ConfigURL string
+}
+
+// ResolveKernel returns the kernel shipped in the given Talos release by reading the siderolabs/pkgs ref from the
+// release's Makefile and the kernel from that ref's Pkgfile, served from the given URLs.
+func ResolveKernel(ctx context.Context, talosSourceURL string, pkgsSourceURL string, kernelOrgURL string, version string) (*Kernel, error) {
+	fetcher := fetch.New()
+
+	makefile, err := fetcher.ReadAll(ctx, talosSourceURL+version+"/Makefile")
+	if err != nil {
+		return nil, err
+	}
+
+	pkgsRef, err := ParsePkgsRef(makefile)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Makefile of %s: %w", version, err)
+	}
+
+	pkgfile, err := fetcher.ReadAll(ctx, pkgsSourceURL+pkgsRef+"/Pkgfile")
+	if err != nil {
+		return nil, err
+	}
+
+	kernel, err := ParseKernel(pkgfile)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Pkgfile of siderolabs/pkgs %s: %w", pkgsRef, err)
+	}
+	kernel.SourcesURL = fmt.Sprintf("%sv%s.x/linux-%s.tar.xz", kernelOrgURL, strings.SplitN(kernel.Version, ".", 2)[0], kernel.Version)
+	kernel.ConfigURL = pkgsSourceURL + pkgsRef + "/" + kernelConfigPath
+
+	return kernel, nil
+}
+
+// ParsePkgsRef returns the siderolabs/pkgs ref declared in the given Talos Makefile. Talos releases may be built from
+// untagged siderolabs/pkgs commits, declared by their `git describe`, in which case the commit is returned.
+func ParsePkgsRef(makefile []byte) (string, error) {
+	matches := pkgsRe.FindSubmatch(makefile)
+	if matches == nil {
+		return "", fmt.Errorf("could not find PKGS")
+	}
+
+	pkgsRef := string(matches[1])
+	if describeMatches := pkgsDescribeRe.FindStringSubmatch(pkgsRef); describeMatches != nil {
+		return describeMatches[1], nil
+	}
+
+	return pkgsRef, nil
+}
+
+// ParseKernel returns the upstream kernel declared in the given siderolabs/pkgs Pkgfile.
+func ParseKernel(pkgfile []byte) (*Kernel, error) {
+	versionMatches := linuxVersionRe.FindSubmatch(pkgfile)
+	if versionMatches == nil {
+		return nil, fmt.Errorf("could not find linux_version")
+	}
+
+	sha256Matches := linuxSHA256Re.FindSubmatch(pkgfile)
+	if sha256Matches == nil {
+		return nil, fmt.Errorf("could not find linux_sha256")
+	}
+
+	return &Kernel{
+		Version: string(versionMatches[1]),
+		SHA256:  string(sha256Matches[1]),
+	}, nil
+}
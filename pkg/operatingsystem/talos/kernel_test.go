@@ -0,0 +1,83 @@
+package talos_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/talos"
+)
+
+const testSHA256 = "4f2e1f3bb0f0d9d7c3f1d1a5c8b4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2"
+
+// Excerpt of https://raw.githubusercontent.com/siderolabs/pkgs/v1.6.0/Pkgfile
+const testPkgfile = `format: v1alpha2
+
+vars:
+  # renovate: datasource=git-tags extractVersion=^v(?<version>.*)$ depName=git://git.kernel.org/pub/scm/linux/kernel/git/stable/linux.git
+  linux_version: 6.1.67
+  linux_sha256: ` + testSHA256 + `
+  linux_sha512: abc
+`
+
+func TestParsePkgsRef(t *testing.T) {
+	var tests = []struct {
+		makefile    string
+		expectedRef string
+		expectedErr bool
+	}{
+		{"TOOLS ?= v1.6.0\nPKGS ?= v1.6.0\n", "v1.6.0", false},
+		{"PKGS_PREFIX ?= ghcr.io/siderolabs\nPKGS ?= v1.6.0-7-g0ea7e9b\n", "0ea7e9b", false},
+		{"PKGS_PREFIX ?= ghcr.io/siderolabs\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedRef, func(t *testing.T) {
+			ref, err := talos.ParsePkgsRef([]byte(tt.makefile))
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRef, ref)
+		})
+	}
+}
+
+func TestParseKernel(t *testing.T) {
+	kernel, err := talos.ParseKernel([]byte(testPkgfile))
+	require.NoError(t, err)
+
+	assert.Equal(t, &talos.Kernel{Version: "6.1.67", SHA256: testSHA256}, kernel)
+
+	_, err = talos.ParseKernel([]byte("vars:\n  linux_version: 6.1.67\n"))
+	assert.Error(t, err)
+}
+
+func TestResolveKernel(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/talos/v1.6.0/Makefile", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PKGS ?= v1.6.0-7-g0ea7e9b\n"))
+	})
+	mux.HandleFunc("/pkgs/0ea7e9b/Pkgfile", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPkgfile))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	kernel, err := talos.ResolveKernel(context.Background(), server.URL+"/talos/", server.URL+"/pkgs/", "https://cdn.kernel.org/pub/linux/kernel/", "v1.6.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, &talos.Kernel{
+		Version:    "6.1.67",
+		SHA256:     testSHA256,
+		SourcesURL: "https://cdn.kernel.org/pub/linux/kernel/v6.x/linux-6.1.67.tar.xz",
+		ConfigURL:  server.URL + "/pkgs/0ea7e9b/kernel/build/config-amd64",
+	}, kernel)
+
+	_, err = talos.ResolveKernel(context.Background(), server.URL+"/talos/", server.URL+"/pkgs/", "https://cdn.kernel.org/pub/linux/kernel/", "v1.5.0")
+	assert.Error(t, err)
+}
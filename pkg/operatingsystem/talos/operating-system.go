@@ -0,0 +1,121 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name represents the name of this operating system, which is also the `ID` in Talos's `/etc/os-release` that
+// falco-driver-loader uses as the target ID.
+const Name = "talos"
+
+// maxConcurrentResolves is the maximum number of releases whose kernels are resolved at once.
+const maxConcurrentResolves = 8
+
+var log = logging.Logger
+
+// Talos implements operatingsystem.OperatingSystem for Talos Linux. Talos has no package manager so its kernels are
+// built from the upstream kernel sources and the configuration Sidero Labs publish for each release.
+type Talos struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+}
+
+// NewTalos returns a new Talos implementation of operatingsystem.OperatingSystem.
+func NewTalos(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Talos{
+		dockerClient: dockerClient,
+	}
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for talos.
+func (s *Talos) GetName() string {
+	return Name
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for talos.
+// Note that KernelPackageNames in this context are the newest Talos release shipping each kernel, e.g. `talos-v1.6.0`.
+func (s *Talos) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	releases, err := ListReleases(ctx, ReleasesURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not list releases: %w", err)
+	}
+
+	releases, err = resolveKernels(ctx, releases)
+	if err != nil {
+		return nil, err
+	}
+
+	latestReleases := LatestReleasePerKernel(releases)
+	packageNames := []string{}
+	for name, release := range latestReleases {
+		if operatingsystem.IsEBPFCompatible(release.Kernel.Version) {
+			packageNames = append(packageNames, name)
+		}
+	}
+	sort.Strings(packageNames)
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.Architecture = kernelMachine
+		ref.Published = latestReleases[ref.Name].Published
+	}), nil
+}
+
+// resolveKernels resolves the kernels of the given releases, at most maxConcurrentResolves at once, and returns the
+// releases whose kernel was resolved. Releases whose kernel cannot be resolved (e.g. as their sources have moved) are
+// logged and skipped rather than failing the whole listing.
+func resolveKernels(ctx context.Context, releases []*Release) ([]*Release, error) {
+	sem := make(chan bool, maxConcurrentResolves)
+	wg := sync.WaitGroup{}
+	for _, release := range releases {
+		wg.Add(1)
+		go func(release *Release) {
+			defer wg.Done()
+			sem <- true
+			defer func() { <-sem }()
+
+			kernel, err := ResolveKernel(ctx, TalosSourceURL, PkgsSourceURL, KernelOrgURL, release.Version)
+			if err != nil {
+				log.Warn().Err(err).Str("release", release.Version).Msg("could not resolve kernel, skipping release")
+				return
+			}
+			release.Kernel = kernel
+		}(release)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resolvedReleases := []*Release{}
+	for _, release := range releases {
+		if release.Kernel != nil {
+			resolvedReleases = append(resolvedReleases, release)
+		}
+	}
+
+	return resolvedReleases, nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for talos.
+func (s *Talos) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	version, err := ReleaseFromName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	kernel, err := ResolveKernel(ctx, TalosSourceURL, PkgsSourceURL, KernelOrgURL, version)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve kernel of %s: %w", version, err)
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, &Release{Version: version, Kernel: kernel})
+}
@@ -0,0 +1,43 @@
+package talos_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/talos"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+	os := talos.NewTalos(cli)
+
+	res, err := os.GetKernelPackageNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+	for _, name := range res.Names() {
+		assert.Regexp(t, regexp.MustCompile(`^talos-v\d+\.\d+\.\d+$`), name)
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := talos.NewTalos(cli)
+
+	res, err := os.GetKernelPackageByName(context.Background(), "talos-v1.6.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "talos", res.OperatingSystem)
+	assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-talos$`), res.KernelRelease)
+	assert.Equal(t, "#1 SMP", res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=talos")
+	assert.Equal(t, "falco_talos_"+res.KernelRelease+"_1", res.ProbeName())
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
@@ -0,0 +1,145 @@
+package talos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// ReleasesURL is the GitHub API endpoint listing Talos releases.
+	ReleasesURL = "https://api.github.com/repos/siderolabs/talos/releases"
+	// MinimumRelease is the oldest Talos release whose kernel is listed, as older releases are no longer supported by
+	// Sidero Labs.
+	MinimumRelease = "v1.5.0"
+
+	releasesPageSize = 100
+)
+
+// releaseVersionRe matches stable Talos release versions, e.g. `v1.6.0` -> 1, 6, 0.
+var releaseVersionRe = regexp.MustCompile(`^v([0-9]+)\.([0-9]+)\.([0-9]+)$`)
+
+// Release represents a Talos release.
+type Release struct {
+	// Version is the version of the release, e.g. `v1.6.0`.
+	Version string
+	// Kernel is the kernel shipped in the release.
+	Kernel *Kernel
+	// Published is when the release was published, or the zero time if unknown.
+	Published time.Time
+}
+
+// Name returns the kernel package name of the Release, e.g. `talos-v1.6.0`.
+func (r *Release) Name() string {
+	return "talos-" + r.Version
+}
+
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ListReleases returns the stable Talos releases since MinimumRelease listed by the GitHub API at the given URL, without
+// their kernels.
+func ListReleases(ctx context.Context, releasesURL string) ([]*Release, error) {
+	fetcher := fetch.New()
+
+	releases := []*Release{}
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s?per_page=%d&page=%d", releasesURL, releasesPageSize, page)
+		body, err := fetcher.ReadAll(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		pageReleases, n, err := ParseReleases(body)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", url, err)
+		}
+		releases = append(releases, pageReleases...)
+
+		if n < releasesPageSize {
+			return releases, nil
+		}
+	}
+}
+
+// ParseReleases returns the stable Talos releases since MinimumRelease described by the given page of GitHub releases,
+// without their kernels, as well as the total number of releases on the page.
+func ParseReleases(releasesJSON []byte) ([]*Release, int, error) {
+	githubReleases := []*githubRelease{}
+	if err := json.Unmarshal(releasesJSON, &githubReleases); err != nil {
+		return nil, 0, err
+	}
+
+	releases := []*Release{}
+	for _, release := range githubReleases {
+		if release.Draft || release.Prerelease || !releaseVersionRe.MatchString(release.TagName) {
+			continue
+		}
+		if compareVersions(release.TagName, MinimumRelease) < 0 {
+			continue
+		}
+		releases = append(releases, &Release{Version: release.TagName, Published: release.PublishedAt})
+	}
+
+	return releases, len(githubReleases), nil
+}
+
+// LatestReleasePerKernel returns the newest of the given releases which ship each kernel, keyed by kernel package
+// name, as releases which share a kernel share its probe. Releases only share a kernel if they build the same upstream
+// kernel with the configuration of the same siderolabs/pkgs ref, as the configuration may change between refs.
+func LatestReleasePerKernel(releases []*Release) map[string]*Release {
+	latestReleases := map[string]*Release{}
+	for _, release := range releases {
+		key := release.Kernel.Version + " " + release.Kernel.ConfigURL
+		latestRelease, ok := latestReleases[key]
+		if ok && compareVersions(latestRelease.Version, release.Version) > 0 {
+			continue
+		}
+		latestReleases[key] = release
+	}
+
+	releasesByName := map[string]*Release{}
+	for _, release := range latestReleases {
+		releasesByName[release.Name()] = release
+	}
+
+	return releasesByName
+}
+
+// ReleaseFromName returns the Talos release version of the given kernel package name, e.g. `talos-v1.6.0` -> `v1.6.0`.
+func ReleaseFromName(name string) (string, error) {
+	version := strings.TrimPrefix(name, "talos-")
+	if version == name || !releaseVersionRe.MatchString(version) {
+		return "", fmt.Errorf("could not parse Talos release from %s", name)
+	}
+
+	return version, nil
+}
+
+// compareVersions returns -1, 0 or 1 if the Talos release version a is older than, the same as or newer than b.
+func compareVersions(a string, b string) int {
+	aMatches := releaseVersionRe.FindStringSubmatch(a)
+	bMatches := releaseVersionRe.FindStringSubmatch(b)
+	for i := 1; i < len(aMatches) && i < len(bMatches); i++ {
+		aPart, _ := strconv.Atoi(aMatches[i])
+		bPart, _ := strconv.Atoi(bMatches[i])
+		if aPart < bPart {
+			return -1
+		}
+		if aPart > bPart {
+			return 1
+		}
+	}
+
+	return 0
+}
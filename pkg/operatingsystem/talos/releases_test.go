@@ -0,0 +1,64 @@
+package talos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/talos"
+)
+
+// Excerpt of https://api.github.com/repos/siderolabs/talos/releases
+const testReleasesJSON = `[
+  {"tag_name": "v1.7.0-alpha.0", "draft": false, "prerelease": true, "published_at": "2023-12-19T18:17:36Z"},
+  {"tag_name": "v1.6.1", "draft": false, "prerelease": false, "published_at": "2023-12-22T12:55:11Z"},
+  {"tag_name": "v1.6.0", "draft": false, "prerelease": false, "published_at": "2023-12-15T15:06:27Z"},
+  {"tag_name": "v1.5.5", "draft": false, "prerelease": false, "published_at": "2023-11-07T14:32:47Z"},
+  {"tag_name": "v1.4.8", "draft": false, "prerelease": false, "published_at": "2023-08-09T10:20:44Z"},
+  {"tag_name": "v1.6.2", "draft": true, "prerelease": false, "published_at": null}
+]`
+
+func TestParseReleases(t *testing.T) {
+	releases, n, err := talos.ParseReleases([]byte(testReleasesJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*talos.Release{
+		{Version: "v1.6.1", Published: time.Date(2023, time.December, 22, 12, 55, 11, 0, time.UTC)},
+		{Version: "v1.6.0", Published: time.Date(2023, time.December, 15, 15, 6, 27, 0, time.UTC)},
+		{Version: "v1.5.5", Published: time.Date(2023, time.November, 7, 14, 32, 47, 0, time.UTC)},
+	}, releases)
+	assert.Equal(t, 6, n)
+}
+
+func TestLatestReleasePerKernel(t *testing.T) {
+	configURL := func(pkgsRef string) string {
+		return talos.PkgsSourceURL + pkgsRef + "/kernel/build/config-amd64"
+	}
+	releases := []*talos.Release{
+		{Version: "v1.6.1", Kernel: &talos.Kernel{Version: "6.1.69", ConfigURL: configURL("v1.6.1")}},
+		{Version: "v1.6.10", Kernel: &talos.Kernel{Version: "6.1.69", ConfigURL: configURL("v1.6.1")}},
+		{Version: "v1.6.0", Kernel: &talos.Kernel{Version: "6.1.67", ConfigURL: configURL("v1.6.0")}},
+		{Version: "v1.5.5", Kernel: &talos.Kernel{Version: "6.1.67", ConfigURL: configURL("v1.6.0")}},
+		// the same upstream kernel built with the configuration of another siderolabs/pkgs ref.
+		{Version: "v1.6.2", Kernel: &talos.Kernel{Version: "6.1.69", ConfigURL: configURL("0ea7e9b")}},
+	}
+
+	assert.Equal(t, map[string]*talos.Release{
+		"talos-v1.6.10": releases[1],
+		"talos-v1.6.0":  releases[2],
+		"talos-v1.6.2":  releases[4],
+	}, talos.LatestReleasePerKernel(releases))
+}
+
+func TestReleaseFromName(t *testing.T) {
+	version, err := talos.ReleaseFromName("talos-v1.6.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.6.0", version)
+
+	_, err = talos.ReleaseFromName("v1.6.0")
+	assert.Error(t, err)
+
+	_, err = talos.ReleaseFromName("talos-latest")
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package ubuntu
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/fetch"
+)
+
+const (
+	// PoolURL is the Ubuntu archive's package pool which contains the kernel source packages for all supported Ubuntu releases.
+	PoolURL = "http://archive.ubuntu.com/ubuntu/pool/main/l/"
+)
+
+var (
+	// hrefRe matches the links in the Apache directory indexes served by the Ubuntu archive.
+	hrefRe = regexp.MustCompile(`href="([^"?/][^"]*)"`)
+	// commonHeadersDebRe matches the architecture independent kernel headers package, e.g.
+	// `linux-headers-5.4.0-100_5.4.0-100.113_all.deb` -> 5.4.0-100, 5.4.0-100.113 or
+	// `linux-hwe-5.15-headers-5.15.0-60_5.15.0-60.66~20.04.1_all.deb` -> 5.15.0-60, 5.15.0-60.66~20.04.1.
+	commonHeadersDebRe = regexp.MustCompile(`^linux(?:-[a-z0-9.-]+)?-headers-([0-9]+\.[0-9]+\.[0-9]+-[0-9]+)_([^_]+)_all\.deb$`)
+)
+
+// HeadersPackage represents the pair of Debian packages which provide the kernel headers for an Ubuntu kernel release.
+type HeadersPackage struct {
+	// ABI is the kernel ABI that the headers are for, e.g. `5.4.0-100`.
+	ABI string
+	// KernelRelease is the kernel release that the headers are for, e.g. `5.4.0-100-generic`.
+	KernelRelease string
+	// Version is the Debian package version of the headers, e.g. `5.4.0-100.113`.
+	Version string
+	// URLs are the URLs of the architecture specific and architecture independent Debian packages.
+	URLs []string
+}
+
+// ListHeadersPackages returns the kernel headers packages of the given flavour available in the Ubuntu archive's
+// package pool at the given URL, keyed by kernel release.
+func ListHeadersPackages(ctx context.Context, poolURL string, flavour *Flavour) (map[string]*HeadersPackage, error) {
+	fetcher := fetch.New()
+
+	poolIndex, err := readIndex(ctx, fetcher, poolURL)
+	if err != nil {
+		return nil, err
+	}
+
+	headersPackages := map[string]*HeadersPackage{}
+	for _, sourceDir := range filterLinks(poolIndex, flavour.sourceDirRe) {
+		sourceURL := poolURL + sourceDir
+		sourceIndex, err := readIndex(ctx, fetcher, sourceURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for kernelRelease, headersPackage := range ParseHeadersPackages(flavour, sourceURL, sourceIndex) {
+			headersPackages[kernelRelease] = headersPackage
+		}
+	}
+
+	return headersPackages, nil
+}
+
+// ParseHeadersPackages returns the kernel headers packages of the given flavour linked to from the given directory index
+// of a kernel source package, keyed by kernel release. Architecture specific packages without a matching architecture
+// independent package are ignored as they cannot be built against.
+func ParseHeadersPackages(flavour *Flavour, sourceURL string, sourceIndex string) map[string]*HeadersPackage {
+	commonDebs := map[string]string{}
+	for _, deb := range filterLinks(sourceIndex, commonHeadersDebRe) {
+		matches := commonHeadersDebRe.FindStringSubmatch(deb)
+		commonDebs[matches[2]] = deb
+	}
+
+	headersDebRe := flavour.headersDebRe()
+	headersPackages := map[string]*HeadersPackage{}
+	for _, deb := range filterLinks(sourceIndex, headersDebRe) {
+		matches := headersDebRe.FindStringSubmatch(deb)
+		abi, version := matches[1], matches[2]
+
+		commonDeb, ok := commonDebs[version]
+		if !ok {
+			continue
+		}
+
+		kernelRelease := abi + "-" + flavour.Name
+		headersPackages[kernelRelease] = &HeadersPackage{
+			ABI:           abi,
+			KernelRelease: kernelRelease,
+			Version:       version,
+			URLs:          []string{sourceURL + deb, sourceURL + commonDeb},
+		}
+	}
+
+	return headersPackages
+}
+
+// KernelVersion returns the value of `uname -v` for the kernel built from this HeadersPackage. Ubuntu only publish the
+// upload number (e.g. `113` in `5.4.0-100.113`) in their package versions, which is all that falco-driver-loader reads
+// from `uname -v`, so the build timestamp is omitted.
+func (hp *HeadersPackage) KernelVersion() string {
+	upload := strings.TrimPrefix(hp.Version, hp.ABI+".")
+
+	return fmt.Sprintf("#%s-Ubuntu SMP", upload)
+}
+
+func readIndex(ctx context.Context, fetcher *fetch.Fetcher, url string) (string, error) {
+	body, err := fetcher.ReadAll(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+func filterLinks(index string, re *regexp.Regexp) []string {
+	links := []string{}
+	for _, matches := range hrefRe.FindAllStringSubmatch(index, -1) {
+		if re.MatchString(matches[1]) {
+			links = append(links, matches[1])
+		}
+	}
+	sort.Strings(links)
+
+	return links
+}
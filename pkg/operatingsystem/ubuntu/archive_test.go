@@ -0,0 +1,109 @@
+package ubuntu_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
+)
+
+// Excerpt of http://archive.ubuntu.com/ubuntu/pool/main/l/linux-hwe-5.15/
+const testSourceIndex = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">
+<html>
+ <head>
+  <title>Index of /ubuntu/pool/main/l/linux-hwe-5.15</title>
+ </head>
+ <body>
+<h1>Index of /ubuntu/pool/main/l/linux-hwe-5.15</h1>
+  <table>
+   <tr><th><a href="?C=N;O=D">Name</a></th></tr>
+<tr><td><a href="/ubuntu/pool/main/l/">Parent Directory</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-60-generic_5.15.0-60.66~20.04.1_amd64.deb">linux-headers-5.15.0-60-generic_5.15.0-60.66~20.04.1_amd64.deb</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-60-generic-64k_5.15.0-60.66~20.04.1_arm64.deb">linux-headers-5.15.0-60-generic-64k_5.15.0-60.66~20.04.1_arm64.deb</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-60-lowlatency_5.15.0-60.66~20.04.1_amd64.deb">linux-headers-5.15.0-60-lowlatency_5.15.0-60.66~20.04.1_amd64.deb</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-67-generic_5.15.0-67.74~20.04.1_amd64.deb">linux-headers-5.15.0-67-generic_5.15.0-67.74~20.04.1_amd64.deb</a></td></tr>
+<tr><td><a href="linux-hwe-5.15-headers-5.15.0-60_5.15.0-60.66~20.04.1_all.deb">linux-hwe-5.15-headers-5.15.0-60_5.15.0-60.66~20.04.1_all.deb</a></td></tr>
+<tr><td><a href="linux-image-5.15.0-60-generic_5.15.0-60.66~20.04.1_amd64.deb">linux-image-5.15.0-60-generic_5.15.0-60.66~20.04.1_amd64.deb</a></td></tr>
+</table>
+</body></html>
+`
+
+func TestParseHeadersPackages(t *testing.T) {
+	sourceURL := "http://archive.ubuntu.com/ubuntu/pool/main/l/linux-hwe-5.15/"
+
+	headersPackages := ubuntu.ParseHeadersPackages(ubuntu.Generic, sourceURL, testSourceIndex)
+
+	// 5.15.0-67-generic is missing its architecture independent package so it should be ignored.
+	assert.Equal(t, map[string]*ubuntu.HeadersPackage{
+		"5.15.0-60-generic": {
+			ABI:           "5.15.0-60",
+			KernelRelease: "5.15.0-60-generic",
+			Version:       "5.15.0-60.66~20.04.1",
+			URLs: []string{
+				sourceURL + "linux-headers-5.15.0-60-generic_5.15.0-60.66~20.04.1_amd64.deb",
+				sourceURL + "linux-hwe-5.15-headers-5.15.0-60_5.15.0-60.66~20.04.1_all.deb",
+			},
+		},
+	}, headersPackages)
+}
+
+// Excerpt of http://archive.ubuntu.com/ubuntu/pool/main/l/linux-aws-5.15/
+const testAWSSourceIndex = `<html>
+<body>
+<tr><td><a href="linux-aws-5.15-headers-5.15.0-1031_5.15.0-1031.35~20.04.1_all.deb">linux-aws-5.15-headers-5.15.0-1031_5.15.0-1031.35~20.04.1_all.deb</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-1031-aws_5.15.0-1031.35~20.04.1_amd64.deb">linux-headers-5.15.0-1031-aws_5.15.0-1031.35~20.04.1_amd64.deb</a></td></tr>
+<tr><td><a href="linux-headers-5.15.0-1031-aws_5.15.0-1031.35~20.04.1_arm64.deb">linux-headers-5.15.0-1031-aws_5.15.0-1031.35~20.04.1_arm64.deb</a></td></tr>
+</body>
+</html>
+`
+
+func TestParseHeadersPackagesFlavours(t *testing.T) {
+	sourceURL := "http://archive.ubuntu.com/ubuntu/pool/main/l/linux-aws-5.15/"
+
+	var tests = []struct {
+		flavour                *ubuntu.Flavour
+		expectedKernelReleases []string
+	}{
+		{ubuntu.AWS, []string{"5.15.0-1031-aws"}},
+		{ubuntu.Generic, []string{}},
+		{ubuntu.GKE, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flavour.Name, func(t *testing.T) {
+			headersPackages := ubuntu.ParseHeadersPackages(tt.flavour, sourceURL, testAWSSourceIndex)
+
+			kernelReleases := []string{}
+			for kernelRelease := range headersPackages {
+				kernelReleases = append(kernelReleases, kernelRelease)
+			}
+			assert.ElementsMatch(t, tt.expectedKernelReleases, kernelReleases)
+		})
+	}
+}
+
+func TestHeadersPackageKernelVersion(t *testing.T) {
+	var tests = []struct {
+		headersPackage        *ubuntu.HeadersPackage
+		expectedKernelVersion string
+	}{
+		{
+			&ubuntu.HeadersPackage{ABI: "5.4.0-100", KernelRelease: "5.4.0-100-generic", Version: "5.4.0-100.113"},
+			"#113-Ubuntu SMP",
+		},
+		{
+			&ubuntu.HeadersPackage{ABI: "5.15.0-60", KernelRelease: "5.15.0-60-generic", Version: "5.15.0-60.66~20.04.1"},
+			"#66~20.04.1-Ubuntu SMP",
+		},
+		{
+			&ubuntu.HeadersPackage{ABI: "5.15.0-1031", KernelRelease: "5.15.0-1031-aws", Version: "5.15.0-1031.35"},
+			"#35-Ubuntu SMP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.headersPackage.Version, func(t *testing.T) {
+			assert.Equal(t, tt.expectedKernelVersion, tt.headersPackage.KernelVersion())
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package ubuntu
+
+import (
+	"fmt"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+)
+
+// DebDownloaderDockerfile represents the contents of a Dockerfile to build the debdownloader image which downloads and
+// extracts Debian packages. We use a recent Ubuntu release as kernel packages are compressed with zstd since Ubuntu
+// 21.10, which older versions of dpkg cannot extract.
+const DebDownloaderDockerfile = `FROM ubuntu:22.04
+RUN apt-get update \
+	&& apt-get install -y ca-certificates curl \
+	&& rm -rf /var/lib/apt/lists/*
+`
+
+// DebDownloaderRepository is the repository to build the debdownloader image under.
+const DebDownloaderRepository = "docker.io/thoughtmachine/falco-debdownloader"
+
+// BuildDebDownloader builds the debdownloader docker image.
+func BuildDebDownloader(dockerClient *docker.Client) (string, error) {
+	imageFQN := fmt.Sprintf("%s:latest", DebDownloaderRepository)
+	err := dockerClient.Build(&docker.BuildOpts{
+		Dockerfile: DebDownloaderDockerfile,
+		Tags:       []string{imageFQN},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not build %s: %w", imageFQN, err)
+	}
+
+	return imageFQN, nil
+}
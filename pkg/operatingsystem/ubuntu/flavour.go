@@ -0,0 +1,67 @@
+package ubuntu
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Name is the name this operating system had before it was split per flavour, which is kept as an alias of the
+// Generic flavour's operating system.
+const Name = "ubuntu"
+
+// Flavour represents an Ubuntu kernel flavour. Ubuntu publish kernels tuned for each cloud provider whose releases are
+// suffixed with their flavour (e.g. `5.15.0-1031-aws`), so each flavour is its own operating system. Note that
+// falco-driver-loader only distinguishes the AWS flavour in its target IDs, see TargetID.
+type Flavour struct {
+	// Name is the suffix of this flavour's kernel releases, e.g. `aws`.
+	Name string
+
+	// sourceDirRe matches the package pool directories of the kernel source packages which build this flavour.
+	sourceDirRe *regexp.Regexp
+}
+
+var (
+	// Generic is the flavour of kernels installed on Ubuntu hosts by default, including the hardware enablement (HWE) kernels.
+	Generic = &Flavour{Name: "generic", sourceDirRe: regexp.MustCompile(`^linux(-hwe(-[0-9.]+)?)?/$`)}
+	// AWS is the flavour of kernels installed on Ubuntu hosts in Amazon Web Services.
+	AWS = &Flavour{Name: "aws", sourceDirRe: regexp.MustCompile(`^linux-aws(-[0-9.]+)?/$`)}
+	// GKE is the flavour of kernels installed on Ubuntu nodes in Google Kubernetes Engine.
+	GKE = &Flavour{Name: "gke", sourceDirRe: regexp.MustCompile(`^linux-gke(-[0-9.]+)?/$`)}
+	// Azure is the flavour of kernels installed on Ubuntu hosts in Microsoft Azure.
+	Azure = &Flavour{Name: "azure", sourceDirRe: regexp.MustCompile(`^linux-azure(-[0-9.]+)?/$`)}
+	// GCP is the flavour of kernels installed on Ubuntu hosts in Google Compute Engine.
+	GCP = &Flavour{Name: "gcp", sourceDirRe: regexp.MustCompile(`^linux-gcp(-[0-9.]+)?/$`)}
+)
+
+// OperatingSystemName returns the name of the operating system for this flavour, e.g. `ubuntu-gke`.
+func (f *Flavour) OperatingSystemName() string {
+	return "ubuntu-" + f.Name
+}
+
+// TargetID returns the target ID that falco-driver-loader resolves for the given Ubuntu kernel release.
+// from: if [[ $KERNEL_RELEASE == *"aws"* ]]; then TARGET_ID="ubuntu-aws"; else TARGET_ID="ubuntu-generic"; fi
+func TargetID(kernelRelease string) string {
+	if strings.Contains(kernelRelease, "aws") {
+		return "ubuntu-aws"
+	}
+
+	return "ubuntu-generic"
+}
+
+// NewUbuntu returns a new ubuntu implementation of operatingsystem.OperatingSystem for this flavour.
+func (f *Flavour) NewUbuntu(dockerClient *docker.Client) operatingsystem.OperatingSystem {
+	return &Ubuntu{
+		dockerClient: dockerClient,
+		flavour:      f,
+	}
+}
+
+// headersDebRe matches this flavour's architecture specific kernel headers package, e.g.
+// `linux-headers-5.15.0-1031-aws_5.15.0-1031.35_amd64.deb` -> 5.15.0-1031, 5.15.0-1031.35.
+func (f *Flavour) headersDebRe() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^linux-headers-([0-9]+\.[0-9]+\.[0-9]+-[0-9]+)-%s_([^_]+)_amd64\.deb$`, regexp.QuoteMeta(f.Name)))
+}
@@ -0,0 +1,37 @@
+package ubuntu_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
+)
+
+func TestProbeName(t *testing.T) {
+	var tests = []struct {
+		flavour           *ubuntu.Flavour
+		kernelRelease     string
+		kernelVersion     string
+		expectedProbeName string
+	}{
+		{ubuntu.Generic, "5.4.0-100-generic", "#113-Ubuntu SMP Thu Feb 3 18:43:29 UTC 2022", "falco_ubuntu-generic_5.4.0-100-generic_113"},
+		{ubuntu.Generic, "5.15.0-60-generic", "#66~20.04.1-Ubuntu SMP Wed Jan 25 09:41:30 UTC 2023", "falco_ubuntu-generic_5.15.0-60-generic_66~20.04.1"},
+		{ubuntu.AWS, "5.15.0-1031-aws", "#35-Ubuntu SMP Fri Feb 10 02:07:18 UTC 2023", "falco_ubuntu-aws_5.15.0-1031-aws_35"},
+		{ubuntu.AWS, "5.15.0-1031-aws", "#35~20.04.1-Ubuntu SMP Sat Feb 11 16:19:06 UTC 2023", "falco_ubuntu-aws_5.15.0-1031-aws_35~20.04.1"},
+		{ubuntu.GKE, "5.15.0-1027-gke", "#32-Ubuntu SMP Tue Feb 21 12:59:48 UTC 2023", "falco_ubuntu-generic_5.15.0-1027-gke_32"},
+		{ubuntu.Azure, "5.15.0-1034-azure", "#41-Ubuntu SMP Fri Feb 10 19:59:45 UTC 2023", "falco_ubuntu-generic_5.15.0-1034-azure_41"},
+		{ubuntu.GCP, "5.15.0-1030-gcp", "#37~20.04.1-Ubuntu SMP Mon Feb 20 04:30:57 UTC 2023", "falco_ubuntu-generic_5.15.0-1030-gcp_37~20.04.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flavour.Name+"-"+tt.kernelVersion, func(t *testing.T) {
+			kp := &operatingsystem.KernelPackage{
+				OperatingSystem: ubuntu.TargetID(tt.kernelRelease),
+				KernelRelease:   tt.kernelRelease,
+				KernelVersion:   tt.kernelVersion,
+			}
+			assert.Equal(t, tt.expectedProbeName, kp.ProbeName())
+		})
+	}
+}
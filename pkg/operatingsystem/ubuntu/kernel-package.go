@@ -0,0 +1,86 @@
+package ubuntu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+const kernelMachine = "x86_64"
+
+// NewKernelPackage returns a new hydrated Ubuntu implementation of operatingsystem.KernelPackage for the given
+// flavour's HeadersPackage.
+func NewKernelPackage(ctx context.Context, dockerClient *docker.Client, flavour *Flavour, headersPackage *HeadersPackage) (*operatingsystem.KernelPackage, error) {
+	kP := &operatingsystem.KernelPackage{
+		OperatingSystem: TargetID(headersPackage.KernelRelease),
+		Name:            headersPackage.KernelRelease,
+		KernelRelease:   headersPackage.KernelRelease,
+		KernelVersion:   headersPackage.KernelVersion(),
+		KernelMachine:   kernelMachine,
+	}
+
+	if err := addSourcesAndConfiguration(ctx, dockerClient, kP, headersPackage); err != nil {
+		return nil, err
+	}
+
+	addOSRelease(kP)
+
+	return kP, nil
+}
+
+// addSourcesAndConfiguration extracts the headers packages into the sources volume and links them to where
+// falco-driver-builder expects to find them (`/usr/src/kernels/<kernel release>`), as well as the conventional
+// `/lib/modules/<kernel release>/build` symlink and configuration in the configuration volume.
+func addSourcesAndConfiguration(ctx context.Context, dockerClient *docker.Client, kp *operatingsystem.KernelPackage, headersPackage *HeadersPackage) error {
+	kp.KernelConfiguration = dockerClient.MustCreateVolume()
+	kp.KernelSources = dockerClient.MustCreateVolume()
+
+	debDownloaderImage, err := BuildDebDownloader(dockerClient)
+	if err != nil {
+		return fmt.Errorf("could not build debdownloader: %w", err)
+	}
+
+	script := `
+set -euo pipefail
+cd "$(mktemp -d)"
+curl -sSfL --remote-name-all %[2]s
+for deb in *.deb; do dpkg -x "${deb}" ./root; done
+cp -a ./root/usr/src/. /usr/src/
+mkdir -p /usr/src/kernels /lib/modules/%[1]s
+ln -sfn ../linux-headers-%[1]s /usr/src/kernels/%[1]s
+ln -sfn /usr/src/kernels/%[1]s /lib/modules/%[1]s/build
+cp /usr/src/linux-headers-%[1]s/.config /lib/modules/%[1]s/config
+`
+
+	_, err = dockerClient.Run(
+		ctx,
+		&docker.RunOpts{
+			Image:      debDownloaderImage,
+			Entrypoint: []string{"/bin/bash"},
+			Cmd:        []string{"-c", fmt.Sprintf(script, kp.KernelRelease, strings.Join(headersPackage.URLs, " "))},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addOSRelease mocks the `/etc/os-release` of an Ubuntu host. falco-driver-loader only reads the `ID` from it and
+// determines the rest of the target ID from the kernel release's flavour, thus the kernel's Ubuntu release does not
+// matter.
+func addOSRelease(kp *operatingsystem.KernelPackage) {
+	kp.OSRelease = operatingsystem.FileContents(`NAME="Ubuntu"
+ID=ubuntu
+ID_LIKE=debian
+PRETTY_NAME="Ubuntu"
+`)
+}
@@ -0,0 +1,44 @@
+package ubuntu_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
+)
+
+func TestGetKernelSourcesAndConfiguration(t *testing.T) {
+	cli := docker.MustClient()
+
+	headersPackages, err := ubuntu.ListHeadersPackages(context.Background(), ubuntu.PoolURL, ubuntu.Generic)
+	require.NoError(t, err)
+
+	// 5.4.0-26-generic is the kernel Ubuntu 20.04 was released with, which is kept in the archive for the lifetime
+	// of the release.
+	headersPackage, ok := headersPackages["5.4.0-26-generic"]
+	require.True(t, ok)
+
+	kp, err := ubuntu.NewKernelPackage(context.Background(), cli, ubuntu.Generic, headersPackage)
+	require.NoError(t, err)
+
+	out, err := cli.Run(
+		context.Background(),
+		&docker.RunOpts{
+			Image:      "docker.io/library/busybox:latest",
+			Entrypoint: []string{"/bin/sh"},
+			Cmd: []string{"-c", "ls /usr/src/kernels/" + kp.KernelRelease + "/Makefile && " +
+				"ls /lib/modules/" + kp.KernelRelease + "/build && " +
+				"grep CONFIG_BPF= /lib/modules/" + kp.KernelRelease + "/config"},
+			Volumes: map[operatingsystem.Volume]string{
+				kp.KernelSources:       "/usr/src/",
+				kp.KernelConfiguration: "/lib/modules/",
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, out, "CONFIG_BPF=y")
+}
@@ -0,0 +1,81 @@
+package ubuntu
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
+)
+
+// Ubuntu implements operatingsystem.OperatingSystem for a flavour of Ubuntu's kernels.
+type Ubuntu struct {
+	operatingsystem.OperatingSystem
+
+	dockerClient *docker.Client
+	flavour      *Flavour
+
+	// headersPackages caches the listing of the Ubuntu archive as the kernel release alone is not enough to locate
+	// its headers packages.
+	headersPackages     map[string]*HeadersPackage
+	headersPackagesLock sync.Mutex
+}
+
+// GetName implements operatingsystem.OperatingSystem.GetName for ubuntu.
+func (s *Ubuntu) GetName() string {
+	return s.flavour.OperatingSystemName()
+}
+
+// GetKernelPackageNames implements operatingsystem.OperatingSystem.GetKernelPackageNames for ubuntu.
+// Note that KernelPackageNames in this context are kernel releases, e.g. `5.4.0-100-generic`.
+func (s *Ubuntu) GetKernelPackageNames(ctx context.Context) (operatingsystem.KernelPackageRefs, error) {
+	headersPackages, err := s.getHeadersPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packageNames := []string{}
+	for kernelRelease := range headersPackages {
+		if operatingsystem.IsEBPFCompatible(kernelRelease) {
+			packageNames = append(packageNames, kernelRelease)
+		}
+	}
+	sort.Strings(packageNames)
+
+	return operatingsystem.NewKernelPackageRefs(packageNames, func(ref *operatingsystem.KernelPackageRef) {
+		ref.KernelRelease = ref.Name
+		ref.Architecture = kernelMachine
+	}), nil
+}
+
+// GetKernelPackageByName implements operatingsystem.OperatingSystem.GetKernelPackageByName for ubuntu.
+func (s *Ubuntu) GetKernelPackageByName(ctx context.Context, name string) (*operatingsystem.KernelPackage, error) {
+	headersPackages, err := s.getHeadersPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headersPackage, ok := headersPackages[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find kernel headers for %s in %s", name, PoolURL)
+	}
+
+	return NewKernelPackage(ctx, s.dockerClient, s.flavour, headersPackage)
+}
+
+func (s *Ubuntu) getHeadersPackages(ctx context.Context) (map[string]*HeadersPackage, error) {
+	s.headersPackagesLock.Lock()
+	defer s.headersPackagesLock.Unlock()
+
+	if s.headersPackages == nil {
+		headersPackages, err := ListHeadersPackages(ctx, PoolURL, s.flavour)
+		if err != nil {
+			return nil, fmt.Errorf("could not list kernel headers packages: %w", err)
+		}
+		s.headersPackages = headersPackages
+	}
+
+	return s.headersPackages, nil
+}
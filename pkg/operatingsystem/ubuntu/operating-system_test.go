@@ -0,0 +1,55 @@
+package ubuntu_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thought-machine/falco-probes/pkg/docker"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem/ubuntu"
+)
+
+func TestGetKernelPackageNames(t *testing.T) {
+	cli := docker.MustClient()
+
+	for _, flavour := range []*ubuntu.Flavour{ubuntu.Generic, ubuntu.AWS, ubuntu.GKE, ubuntu.Azure, ubuntu.GCP} {
+		flavour := flavour
+		t.Run(flavour.Name, func(t *testing.T) {
+			os := flavour.NewUbuntu(cli)
+
+			res, err := os.GetKernelPackageNames(context.Background())
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, res)
+			for _, name := range res.Names() {
+				assert.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+-\d+-`+flavour.Name+`$`), name)
+			}
+		})
+	}
+}
+
+func TestGetKernelPackageByName(t *testing.T) {
+	cli := docker.MustClient()
+	os := ubuntu.AWS.NewUbuntu(cli)
+
+	// The Ubuntu archive only keeps the latest kernels so we cannot hardcode a kernel package name.
+	names, err := os.GetKernelPackageNames(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	name := names[len(names)-1].Name
+
+	res, err := os.GetKernelPackageByName(context.Background(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ubuntu-aws", res.OperatingSystem)
+	assert.Equal(t, name, res.KernelRelease)
+	assert.Regexp(t, regexp.MustCompile(`^#\d+.*-Ubuntu SMP$`), res.KernelVersion)
+	assert.Equal(t, "x86_64", res.KernelMachine)
+	assert.Contains(t, res.OSRelease, "ID=ubuntu")
+	assert.Contains(t, res.ProbeName(), "falco_ubuntu-aws_"+name+"_")
+
+	assert.NotEmpty(t, res.KernelConfiguration)
+	assert.NotEmpty(t, res.KernelSources)
+}
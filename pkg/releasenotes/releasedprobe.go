@@ -41,7 +41,16 @@ func KernelPackageFromProbeName(probe string) string {
 			kernelPkg = probeSplit[2] // ... remove the 'falco_amazonlinux2_' prefix
 		}
 
-		kernelPkg = kernelPkg[:strings.Index(kernelPkg, ".amzn2.")+6] // ... trim everything from ".amzn2." (inclusive)
+		// ... aarch64 kernel packages keep their ".aarch64" suffix to distinguish them from x86_64 ones.
+		if !strings.HasSuffix(kernelPkg, ".amzn2.aarch64") {
+			kernelPkg = kernelPkg[:strings.Index(kernelPkg, ".amzn2.")+6] // ... trim everything from ".amzn2." (inclusive)
+		}
+	case strings.Contains(probe, ".amzn2023."):
+		if probeSplit := strings.Split(probe, "_"); len(probeSplit) > 3 && probeSplit[2] != "" {
+			kernelPkg = probeSplit[2] // ... remove the 'falco_amazonlinux2023_' prefix
+		}
+
+		kernelPkg = kernelPkg[:strings.Index(kernelPkg, ".amzn2023.")+9] // ... trim everything from ".amzn2023." (inclusive)
 	}
 
 	return kernelPkg
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/thought-machine/falco-probes/internal/logging"
+	"github.com/thought-machine/falco-probes/pkg/operatingsystem"
 	"github.com/thought-machine/falco-probes/pkg/repository"
 
 	"github.com/google/go-github/v37/github"
@@ -158,12 +159,23 @@ func (ghr *GHReleases) EnsureReleaseForDriverVersion(driverVersion string) (*git
 	}
 
 	// release does not exist, create it
-	// truncate the driverVersion for the release tag as "branch or tag names consisting of 40 hex characters are not allowed."
-	tagName := driverVersion[:8]
 	release, err := ghr.ghClient.CreateRelease(&github.RepositoryRelease{
 		Name:    github.String(driverVersion),
-		TagName: github.String(tagName),
+		TagName: github.String(ReleaseTagName(driverVersion)),
 	})
 
 	return release, err
 }
+
+// ReleaseTagName returns the tag of the release for the given driver version, which may be an
+// operatingsystem.DriverVersionPath for probes of other machines than x86_64 (e.g. `<driver version>-aarch64`).
+func ReleaseTagName(driverVersion string) string {
+	driverVersion, kernelMachine := operatingsystem.ParseDriverVersionPath(driverVersion)
+	// truncate the driverVersion for the release tag as "branch or tag names consisting of 40 hex characters are not allowed."
+	tagName := driverVersion[:8]
+	if kernelMachine != operatingsystem.MachineX86_64 {
+		tagName += "-" + kernelMachine
+	}
+
+	return tagName
+}
@@ -93,3 +93,9 @@ func TestEnsureReleaseForDriverVersion(t *testing.T) {
 
 	cleanupTestReleases(t)
 }
+
+func TestReleaseTagName(t *testing.T) {
+	assert.Equal(t, "17f5df52", ghreleases.ReleaseTagName("17f5df52a7d9ed6bb12d3b1768460def8439936d"))
+	assert.Equal(t, "17f5df52-aarch64", ghreleases.ReleaseTagName("17f5df52a7d9ed6bb12d3b1768460def8439936d/aarch64"))
+	assert.Equal(t, "3.0.1+dr-aarch64", ghreleases.ReleaseTagName("3.0.1+driver/aarch64"))
+}